@@ -0,0 +1,259 @@
+package gphotos
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// AlbumCache is an AlbumsRequests that caches the results of Get and List for a TTL.
+// Albums are cached per *http.Client and looked up by ID with Get or by title with AlbumsByTitle.
+// Entries are invalidated when the same client calls Create, Patch, Share, Unshare, BatchAddMediaItems or BatchRemoveMediaItems.
+// Results of Get and List that were in flight during an invalidation are returned but not cached.
+// Expired entries are dropped at most once per TTL when the cache is used, together with clients left without entries.
+// Call Forget to drop a client before then.
+// It is safe for concurrent use. To cache every call of the package, replace Albums:
+//
+//	gphotos.Albums = gphotos.NewAlbumCache(gphotos.Albums, time.Minute)
+type AlbumCache struct {
+	next AlbumsRequests
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	clients map[*http.Client]*albumCacheEntries
+	swept   time.Time
+}
+
+type albumCacheEntries struct {
+	albums map[string]albumCacheEntry
+	lists  map[string]albumListCacheEntry
+
+	// generation is incremented by Invalidate, so that results fetched before it are not stored.
+	generation uint64
+}
+
+type albumCacheEntry struct {
+	album   Album
+	expires time.Time
+}
+
+type albumListCacheEntry struct {
+	response AlbumsListResponse
+	expires  time.Time
+}
+
+// NewAlbumCache returns an AlbumCache calling next on misses.
+func NewAlbumCache(next AlbumsRequests, ttl time.Duration) *AlbumCache {
+	return &AlbumCache{
+		next:    next,
+		ttl:     ttl,
+		clients: map[*http.Client]*albumCacheEntries{},
+	}
+}
+
+func (cache *AlbumCache) baseURL() string {
+	return cache.next.baseURL()
+}
+
+// entries must be called with the lock held.
+func (cache *AlbumCache) entries(client *http.Client) *albumCacheEntries {
+	cache.sweep(time.Now())
+	entries, ok := cache.clients[client]
+	if !ok {
+		entries = &albumCacheEntries{
+			albums: map[string]albumCacheEntry{},
+			lists:  map[string]albumListCacheEntry{},
+		}
+		cache.clients[client] = entries
+	}
+	return entries
+}
+
+// sweep removes the expired entries, and the clients without entries, if a TTL has passed since the last sweep.
+// A removed client gets new entries, so results in flight for it are not stored.
+// It must be called with the lock held.
+func (cache *AlbumCache) sweep(now time.Time) {
+	if now.Sub(cache.swept) < cache.ttl {
+		return
+	}
+	cache.swept = now
+	for client, entries := range cache.clients {
+		for id, entry := range entries.albums {
+			if !now.Before(entry.expires) {
+				delete(entries.albums, id)
+			}
+		}
+		for key, entry := range entries.lists {
+			if !now.Before(entry.expires) {
+				delete(entries.lists, key)
+			}
+		}
+		if len(entries.albums) == 0 && len(entries.lists) == 0 {
+			delete(cache.clients, client)
+		}
+	}
+}
+
+// current reports whether entries at generation are still the entries of the client.
+// It must be called with the lock held.
+func (cache *AlbumCache) current(client *http.Client, entries *albumCacheEntries, generation uint64) bool {
+	return cache.clients[client] == entries && entries.generation == generation
+}
+
+// store must be called with the lock held.
+func (cache *AlbumCache) store(client *http.Client, albums ...Album) {
+	entries := cache.entries(client)
+	expires := time.Now().Add(cache.ttl)
+	for _, album := range albums {
+		if album.ID != "" {
+			entries.albums[album.ID] = albumCacheEntry{album: album, expires: expires}
+		}
+	}
+}
+
+// Invalidate is a method that removes the album and every cached List result of the client.
+// If albumID is empty, only the List results are removed.
+func (cache *AlbumCache) Invalidate(client *http.Client, albumID string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entries := cache.entries(client)
+	if albumID != "" {
+		delete(entries.albums, albumID)
+	}
+	entries.lists = map[string]albumListCacheEntry{}
+	entries.generation++
+}
+
+// Forget is a method that removes every entry of the client, such as a client that is no longer used.
+func (cache *AlbumCache) Forget(client *http.Client) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	delete(cache.clients, client)
+}
+
+// Purge is a method that removes every entry of every client.
+func (cache *AlbumCache) Purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.clients = map[*http.Client]*albumCacheEntries{}
+}
+
+// AlbumsByTitle returns the cached albums of the client with the title. Expired albums are not returned.
+func (cache *AlbumCache) AlbumsByTitle(client *http.Client, title string) []Album {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	now := time.Now()
+	var albums []Album
+	for _, entry := range cache.entries(client).albums {
+		if entry.album.Title == title && now.Before(entry.expires) {
+			albums = append(albums, entry.album)
+		}
+	}
+	return albums
+}
+
+func (cache *AlbumCache) AddEnrichment(client *http.Client, albumID string, request AlbumsAddEnrichmentRequest) (AlbumsAddEnrichmentResponse, error) {
+	return cache.next.AddEnrichment(client, albumID, request)
+}
+
+func (cache *AlbumCache) BatchAddMediaItems(client *http.Client, albumID string, request AlbumsBatchAddMediaItemsRequest) error {
+	defer cache.Invalidate(client, albumID)
+	return cache.next.BatchAddMediaItems(client, albumID, request)
+}
+
+func (cache *AlbumCache) BatchRemoveMediaItems(client *http.Client, albumID string, request AlbumsBatchRemoveMediaItemsRequest) error {
+	defer cache.Invalidate(client, albumID)
+	return cache.next.BatchRemoveMediaItems(client, albumID, request)
+}
+
+func (cache *AlbumCache) Create(client *http.Client, request AlbumsCreateRequest) (AlbumsCreateResponse, error) {
+	response, err := cache.next.Create(client, request)
+	cache.Invalidate(client, "")
+	if err == nil {
+		cache.mu.Lock()
+		cache.store(client, Album(response))
+		cache.mu.Unlock()
+	}
+	return response, err
+}
+
+func (cache *AlbumCache) Get(client *http.Client, albumID string) (AlbumsGetResponse, error) {
+	cache.mu.Lock()
+	entries := cache.entries(client)
+	entry, ok := entries.albums[albumID]
+	generation := entries.generation
+	cache.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return AlbumsGetResponse(entry.album), nil
+	}
+
+	response, err := cache.next.Get(client, albumID)
+	if err != nil {
+		return response, err
+	}
+	cache.mu.Lock()
+	if cache.current(client, entries, generation) {
+		cache.store(client, Album(response))
+	}
+	cache.mu.Unlock()
+	return response, nil
+}
+
+func (cache *AlbumCache) List(client *http.Client, queries ...ListQuery) (AlbumsListResponse, error) {
+	values := url.Values{}
+	for _, query := range queries {
+		query(&values)
+	}
+	key := values.Encode()
+
+	cache.mu.Lock()
+	entries := cache.entries(client)
+	entry, ok := entries.lists[key]
+	generation := entries.generation
+	cache.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		response := entry.response
+		response.Albums = append([]Album(nil), entry.response.Albums...)
+		return response, nil
+	}
+
+	response, err := cache.next.List(client, queries...)
+	if err != nil {
+		return response, err
+	}
+	cache.mu.Lock()
+	if cache.current(client, entries, generation) {
+		entries.lists[key] = albumListCacheEntry{
+			response: AlbumsListResponse{
+				Albums:        append([]Album(nil), response.Albums...),
+				NextPageToken: response.NextPageToken,
+			},
+			expires: time.Now().Add(cache.ttl),
+		}
+		cache.store(client, response.Albums...)
+	}
+	cache.mu.Unlock()
+	return response, nil
+}
+
+func (cache *AlbumCache) Patch(client *http.Client, album Album, updateMask ...AlbumField) (AlbumsPatchResponse, error) {
+	response, err := cache.next.Patch(client, album, updateMask...)
+	cache.Invalidate(client, album.ID)
+	if err == nil {
+		cache.mu.Lock()
+		cache.store(client, Album(response))
+		cache.mu.Unlock()
+	}
+	return response, err
+}
+
+func (cache *AlbumCache) Share(client *http.Client, albumID string, request AlbumsShareRequest) (AlbumsShareResponse, error) {
+	defer cache.Invalidate(client, albumID)
+	return cache.next.Share(client, albumID, request)
+}
+
+func (cache *AlbumCache) Unshare(client *http.Client, albumID string) error {
+	defer cache.Invalidate(client, albumID)
+	return cache.next.Unshare(client, albumID)
+}
@@ -0,0 +1,261 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AlbumIndex is a reverse index from media items to the albums containing them, cached in a JSON file.
+// The API has no method that returns the albums of a media item, so the index is built with
+// Albums.List, SharedAlbums.List and MediaItems.Search per album.
+// Use OpenAlbumIndex to create an AlbumIndex. It is safe for concurrent use.
+type AlbumIndex struct {
+	filePath string
+
+	mu         sync.RWMutex
+	data       albumIndexData
+	membership albumMembership
+}
+
+// AlbumIndexEntry represents an album and its media items in an AlbumIndex.
+type AlbumIndexEntry struct {
+	Album        Album    `json:"album"`
+	Shared       bool     `json:"shared,omitempty"`
+	MediaItemIDs []string `json:"mediaItemIds,omitempty"`
+}
+
+type albumIndexData struct {
+	RefreshedAt  time.Time                  `json:"refreshedAt"`
+	Albums       map[string]AlbumIndexEntry `json:"albums"`
+	MediaItemIDs []string                   `json:"mediaItemIds,omitempty"`
+}
+
+// AlbumIndexRefreshOptions is options of AlbumIndex.Refresh method.
+type AlbumIndexRefreshOptions struct {
+	// Full searches every album and lists every media item with MediaItems.List.
+	// The first Refresh of an AlbumIndex is always full.
+	Full bool
+}
+
+// AlbumIndexRefreshResult is the result of AlbumIndex.Refresh method.
+type AlbumIndexRefreshResult struct {
+	AlbumsSearched int
+	AlbumsKept     int
+	AlbumsRemoved  int
+}
+
+// OpenAlbumIndex reads an AlbumIndex from filePath. If the file doesn't exist, an empty AlbumIndex is returned.
+func OpenAlbumIndex(filePath string) (*AlbumIndex, error) {
+	index := &AlbumIndex{filePath: filePath}
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &index.data); err != nil {
+			return nil, err
+		}
+	}
+	if index.data.Albums == nil {
+		index.data.Albums = map[string]AlbumIndexEntry{}
+	}
+	index.rebuild()
+	return index, nil
+}
+
+// RefreshedAt returns the time of the last Refresh.
+func (index *AlbumIndex) RefreshedAt() time.Time {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return index.data.RefreshedAt
+}
+
+// Refresh is a method that updates the AlbumIndex from the library and saves it.
+// Unless the refresh is full, only the albums whose item count or cover changed are searched,
+// and media items created since the previous Refresh are added to the library by searching their creation dates.
+// Media items swapped in an album without changing its count or cover, deleted media items,
+// and media items uploaded with older creation dates are only picked up by a full Refresh.
+func (index *AlbumIndex) Refresh(client *http.Client, options AlbumIndexRefreshOptions) (AlbumIndexRefreshResult, error) {
+	return index.refresh(client, options, nil)
+}
+
+// refresh calls fn, if not nil, for each media item found in the searched albums and the library.
+func (index *AlbumIndex) refresh(client *http.Client, options AlbumIndexRefreshOptions, fn func(MediaItem) error) (AlbumIndexRefreshResult, error) {
+	var result AlbumIndexRefreshResult
+	refreshedAt := time.Now().UTC()
+
+	index.mu.RLock()
+	full := options.Full || index.data.RefreshedAt.IsZero()
+	since := index.data.RefreshedAt
+	old := make(map[string]AlbumIndexEntry, len(index.data.Albums))
+	for id, entry := range index.data.Albums {
+		old[id] = entry
+	}
+	oldIDs := index.data.MediaItemIDs
+	index.mu.RUnlock()
+
+	entries := map[string]*AlbumIndexEntry{}
+	err := walkAlbums(client, true, func(album Album, shared bool) (func(MediaItem) error, error) {
+		if entry, ok := old[album.ID]; ok && !full &&
+			entry.Album.MediaItemsCount == album.MediaItemsCount &&
+			entry.Album.CoverPhotoMediaItemID == album.CoverPhotoMediaItemID {
+			entries[album.ID] = &AlbumIndexEntry{Album: album, Shared: shared, MediaItemIDs: entry.MediaItemIDs}
+			result.AlbumsKept++
+			return nil, nil
+		}
+		entry := &AlbumIndexEntry{Album: album, Shared: shared}
+		entries[album.ID] = entry
+		result.AlbumsSearched++
+		return func(item MediaItem) error {
+			entry.MediaItemIDs = append(entry.MediaItemIDs, item.ID)
+			if fn != nil {
+				return fn(item)
+			}
+			return nil
+		}, nil
+	})
+	if err != nil {
+		return result, err
+	}
+	albums := make(map[string]AlbumIndexEntry, len(entries))
+	for id, entry := range entries {
+		albums[id] = *entry
+	}
+	for id := range old {
+		if _, ok := albums[id]; !ok {
+			result.AlbumsRemoved++
+		}
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	collect := func(item MediaItem) error {
+		if !seen[item.ID] {
+			seen[item.ID] = true
+			ids = append(ids, item.ID)
+		}
+		if fn != nil {
+			return fn(item)
+		}
+		return nil
+	}
+	if full {
+		err = listAllMediaItems(client, collect)
+	} else {
+		for _, id := range oldIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		// Media items are searched by creation date, so allow for time zones and clock skew.
+		var request MediaItemsSearchRequest
+		request, err = NewSearch().Between(since.AddDate(0, 0, -2), refreshedAt.AddDate(0, 0, 1)).IncludeArchived().Build()
+		if err == nil {
+			err = searchAllMediaItems(client, request, collect)
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	index.mu.Lock()
+	index.data = albumIndexData{RefreshedAt: refreshedAt, Albums: albums, MediaItemIDs: ids}
+	index.rebuild()
+	index.mu.Unlock()
+
+	return result, index.Save()
+}
+
+// Save is a method that writes the AlbumIndex to its file.
+func (index *AlbumIndex) Save() error {
+	index.mu.RLock()
+	b, err := json.Marshal(index.data)
+	index.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(index.filePath+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(index.filePath+".tmp", index.filePath)
+}
+
+// AlbumsContaining returns the indexed albums containing the media item, sorted by title.
+func (index *AlbumIndex) AlbumsContaining(mediaItemID string) []Album {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return index.membership.containing(mediaItemID)
+}
+
+// Albums returns the indexed albums sorted by title.
+func (index *AlbumIndex) Albums() []Album {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	albums := make([]Album, 0, len(index.data.Albums))
+	for _, entry := range index.data.Albums {
+		albums = append(albums, entry.Album)
+	}
+	sort.Slice(albums, func(i, j int) bool { return albums[i].Title < albums[j].Title })
+	return albums
+}
+
+// mediaItemIDs returns the IDs of the media items of the indexed album, in album order.
+func (index *AlbumIndex) mediaItemIDs(albumID string) []string {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return index.data.Albums[albumID].MediaItemIDs
+}
+
+// ItemsInNoAlbum returns the IDs of the media items in the library that are in no indexed album.
+func (index *AlbumIndex) ItemsInNoAlbum() []string {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	var ids []string
+	for _, id := range index.data.MediaItemIDs {
+		if len(index.membership.byItem[id]) == 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// rebuild must be called with the lock held.
+func (index *AlbumIndex) rebuild() {
+	index.membership = albumMembership{}
+	for _, entry := range index.data.Albums {
+		index.membership.add(entry.Album, entry.MediaItemIDs)
+	}
+}
+
+// albumMembership is a reverse index from media items to the albums containing them.
+type albumMembership struct {
+	albums map[string]Album
+	byItem map[string][]string // media item ID -> album IDs
+}
+
+func (membership *albumMembership) add(album Album, mediaItemIDs []string) {
+	if membership.albums == nil {
+		membership.albums = map[string]Album{}
+		membership.byItem = map[string][]string{}
+	}
+	membership.albums[album.ID] = album
+	for _, id := range mediaItemIDs {
+		membership.byItem[id] = append(membership.byItem[id], album.ID)
+	}
+}
+
+// containing returns the albums containing the media item, sorted by title.
+func (membership albumMembership) containing(mediaItemID string) []Album {
+	var albums []Album
+	for _, albumID := range membership.byItem[mediaItemID] {
+		albums = append(albums, membership.albums[albumID])
+	}
+	sort.Slice(albums, func(i, j int) bool { return albums[i].Title < albums[j].Title })
+	return albums
+}
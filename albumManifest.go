@@ -0,0 +1,404 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AlbumManifests is the only instance of AlbumManifestMethods(https://godoc.org/github.com/Q-Brains/gphotos#AlbumManifestMethods).
+var AlbumManifests AlbumManifestMethods = albumManifestMethods{}
+
+// AlbumManifestMethods is a collection of methods that manage albums declared in an AlbumManifest.
+// The only instance of AlbumManifestMethods is AlbumManifests(https://godoc.org/github.com/Q-Brains/gphotos#AlbumManifests).
+type AlbumManifestMethods interface {
+	// Load is a method that reads an AlbumManifest from a JSON file, or from a YAML file if its extension is .yaml or .yml.
+	Load(filePath string) (AlbumManifest, error)
+
+	// Plan is a method that compares the manifest with the live albums and returns the actions Apply would take.
+	// The state file records the albums, uploads and enrichments created by previous applies. It may not exist.
+	Plan(client *http.Client, manifest AlbumManifest, statePath string) (AlbumPlan, error)
+
+	// Apply is a method that executes the plan of the manifest and records the created IDs in the state file.
+	// Applying the same manifest again does nothing. On error, the state of the actions done so far is saved.
+	Apply(client *http.Client, manifest AlbumManifest, statePath string) (AlbumPlan, error)
+}
+
+// AlbumManifest declares albums. In YAML, the keys are the same as in JSON.
+// Only block mappings and sequences, plain and quoted scalars, single-line flow collections and comments are supported.
+// Plain scalars read as numbers or booleans, such as a title 2024, are decoded as strings into string fields.
+type AlbumManifest struct {
+	Albums []AlbumSpec `json:"albums"`
+}
+
+// AlbumSpec declares an album of an AlbumManifest.
+// Media items can only be added to an album created by the app, so MediaItemIDs must be media items the app can add.
+type AlbumSpec struct {
+	// Key identifies the album in the state file. Renaming the album keeps its Key.
+	Key   string `json:"key"`
+	Title string `json:"title"`
+
+	// Files are uploaded into the album once. Their media item IDs are recorded in the state file.
+	Files        []string `json:"files,omitempty"`
+	MediaItemIDs []string `json:"mediaItemIds,omitempty"`
+
+	// Enrichments are appended to the album once. The API can't read enrichments back,
+	// so they are tracked only in the state file, and removing one from the manifest doesn't delete it.
+	Enrichments []NewEnrichmentItem `json:"enrichments,omitempty"`
+
+	// Cover is a file of Files or a media item ID.
+	Cover string `json:"cover,omitempty"`
+
+	// Share shares the album with the options. If nil, the sharing of the album is left as is.
+	Share *SharedAlbumOptions `json:"share,omitempty"`
+
+	// Unshare unshares the album. It can't be set with Share.
+	Unshare bool `json:"unshare,omitempty"`
+}
+
+// AlbumPlan is the list of actions of AlbumManifests.Plan and AlbumManifests.Apply methods.
+type AlbumPlan struct {
+	Actions []AlbumPlanAction `json:"actions,omitempty"`
+}
+
+// AlbumPlanAction is an action on an album.
+type AlbumPlanAction struct {
+	Key    string `json:"key"`
+	Kind   string `json:"kind"`
+	Target string `json:"target,omitempty"`
+}
+
+// Kinds of AlbumPlanAction.
+const (
+	AlbumPlanCreate  = "create"
+	AlbumPlanRename  = "rename"
+	AlbumPlanUpload  = "upload"
+	AlbumPlanAdd     = "add"
+	AlbumPlanRemove  = "remove"
+	AlbumPlanEnrich  = "enrich"
+	AlbumPlanCover   = "cover"
+	AlbumPlanShare   = "share"
+	AlbumPlanUnshare = "unshare"
+)
+
+// IsEmpty reports whether the AlbumPlan contains no actions.
+func (plan AlbumPlan) IsEmpty() bool {
+	return len(plan.Actions) == 0
+}
+
+// String returns a human-readable AlbumPlan.
+func (plan AlbumPlan) String() string {
+	if plan.IsEmpty() {
+		return "No changes.\n"
+	}
+	var sb strings.Builder
+	for _, action := range plan.Actions {
+		sign := "~"
+		switch action.Kind {
+		case AlbumPlanCreate, AlbumPlanUpload, AlbumPlanAdd, AlbumPlanEnrich:
+			sign = "+"
+		case AlbumPlanRemove, AlbumPlanUnshare:
+			sign = "-"
+		}
+		fmt.Fprintf(&sb, "%s %s %s", sign, action.Key, action.Kind)
+		if action.Target != "" {
+			fmt.Fprintf(&sb, " %s", action.Target)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+type albumManifestState struct {
+	Albums map[string]*albumManifestAlbumState `json:"albums"`
+}
+
+type albumManifestAlbumState struct {
+	AlbumID     string            `json:"albumId"`
+	Files       map[string]string `json:"files,omitempty"`
+	Enrichments map[string]string `json:"enrichments,omitempty"`
+}
+
+type albumManifestMethods struct{}
+
+func (manifests albumManifestMethods) Load(filePath string) (AlbumManifest, error) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return AlbumManifest{}, err
+	}
+	var manifest AlbumManifest
+	if ext := strings.ToLower(filepath.Ext(filePath)); ext == ".yaml" || ext == ".yml" {
+		err = yamlUnmarshal(b, &manifest)
+	} else {
+		err = json.Unmarshal(b, &manifest)
+	}
+	if err != nil {
+		return AlbumManifest{}, err
+	}
+	keys := map[string]bool{}
+	for _, spec := range manifest.Albums {
+		if spec.Key == "" || spec.Title == "" {
+			return AlbumManifest{}, errors.New("album without key or title in " + filePath)
+		}
+		if keys[spec.Key] {
+			return AlbumManifest{}, errors.New("duplicate album key in " + filePath + ": " + spec.Key)
+		}
+		if spec.Share != nil && spec.Unshare {
+			return AlbumManifest{}, errors.New("album with both share and unshare in " + filePath + ": " + spec.Key)
+		}
+		keys[spec.Key] = true
+	}
+	return manifest, nil
+}
+
+func (manifests albumManifestMethods) Plan(client *http.Client, manifest AlbumManifest, statePath string) (AlbumPlan, error) {
+	state, err := loadAlbumManifestState(statePath)
+	if err != nil {
+		return AlbumPlan{}, err
+	}
+	var plan AlbumPlan
+	for _, spec := range manifest.Albums {
+		if err := reconcileAlbumSpec(client, spec, state, &plan, false); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+func (manifests albumManifestMethods) Apply(client *http.Client, manifest AlbumManifest, statePath string) (AlbumPlan, error) {
+	state, err := loadAlbumManifestState(statePath)
+	if err != nil {
+		return AlbumPlan{}, err
+	}
+	var plan AlbumPlan
+	for _, spec := range manifest.Albums {
+		err := reconcileAlbumSpec(client, spec, state, &plan, true)
+		if saveErr := saveAlbumManifestState(statePath, state); err == nil {
+			err = saveErr
+		}
+		if err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+func loadAlbumManifestState(statePath string) (*albumManifestState, error) {
+	state := &albumManifestState{}
+	b, err := ioutil.ReadFile(statePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, state); err != nil {
+			return nil, err
+		}
+	}
+	if state.Albums == nil {
+		state.Albums = map[string]*albumManifestAlbumState{}
+	}
+	return state, nil
+}
+
+func saveAlbumManifestState(statePath string, state *albumManifestState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(statePath+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(statePath+".tmp", statePath)
+}
+
+// reconcileAlbumSpec appends the actions needed for the spec to plan, and executes them if apply is set.
+func reconcileAlbumSpec(client *http.Client, spec AlbumSpec, state *albumManifestState, plan *AlbumPlan, apply bool) error {
+	act := func(kind string, target string) {
+		plan.Actions = append(plan.Actions, AlbumPlanAction{Key: spec.Key, Kind: kind, Target: target})
+	}
+
+	st := state.Albums[spec.Key]
+	if st == nil {
+		st = &albumManifestAlbumState{}
+	}
+	if apply {
+		state.Albums[spec.Key] = st
+	}
+
+	var album Album
+	exists := false
+	if st.AlbumID != "" {
+		resp, err := Albums.Get(client, st.AlbumID)
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+			err = nil
+		} else if err == nil {
+			album, exists = Album(resp), true
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		act(AlbumPlanCreate, spec.Title)
+		if apply {
+			created, err := createAlbum(client, spec.Title)
+			if err != nil {
+				return err
+			}
+			album, exists = created, true
+			*st = albumManifestAlbumState{AlbumID: created.ID}
+		} else {
+			// A new album starts empty, so everything is planned against nothing.
+			st = &albumManifestAlbumState{}
+		}
+	} else if album.Title != spec.Title {
+		act(AlbumPlanRename, spec.Title)
+		if apply {
+			album.Title = spec.Title
+			if _, err := Albums.Patch(client, album, AlbumTitleField); err != nil {
+				return err
+			}
+		}
+	}
+
+	var current []string
+	if exists && st.AlbumID != "" {
+		err := searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: album.ID}, func(item MediaItem) error {
+			current = append(current, item.ID)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Files are uploaded into the album, unless an earlier apply uploaded them.
+	wanted := append([]string(nil), spec.MediaItemIDs...)
+	for _, file := range spec.Files {
+		if id, ok := st.Files[file]; ok {
+			wanted = append(wanted, id)
+			continue
+		}
+		act(AlbumPlanUpload, file)
+		if apply {
+			item, err := uploadIntoAlbum(client, album.ID, file)
+			if err != nil {
+				return err
+			}
+			if st.Files == nil {
+				st.Files = map[string]string{}
+			}
+			st.Files[file] = item.ID
+			current = append(current, item.ID)
+			wanted = append(wanted, item.ID)
+		}
+	}
+
+	added := subtractIDs(wanted, current)
+	for _, id := range added {
+		act(AlbumPlanAdd, id)
+	}
+	removed := subtractIDs(current, wanted)
+	for _, id := range removed {
+		act(AlbumPlanRemove, id)
+	}
+	if apply {
+		if len(added) > 0 {
+			result, _ := BulkAddMediaItems(client, album.ID, added, BulkOptions{})
+			if len(result.Failed) > 0 {
+				return errors.New("adding " + result.Failed[0].MediaItemID + " to " + spec.Key + ": " + result.Failed[0].Err.Error())
+			}
+		}
+		if len(removed) > 0 {
+			result, _ := BulkRemoveMediaItems(client, album.ID, removed, BulkOptions{})
+			if len(result.Failed) > 0 {
+				return errors.New("removing " + result.Failed[0].MediaItemID + " from " + spec.Key + ": " + result.Failed[0].Err.Error())
+			}
+		}
+	}
+
+	for _, enrichment := range spec.Enrichments {
+		b, err := json.Marshal(enrichment)
+		if err != nil {
+			return err
+		}
+		key := string(b)
+		if _, ok := st.Enrichments[key]; ok {
+			continue
+		}
+		act(AlbumPlanEnrich, key)
+		if apply {
+			resp, err := Albums.AddEnrichment(client, album.ID, AlbumsAddEnrichmentRequest{
+				NewEnrichmentItem: enrichment,
+				AlbumPosition:     AlbumPosition{Position: LastInAlbum},
+			})
+			if err != nil {
+				return err
+			}
+			if st.Enrichments == nil {
+				st.Enrichments = map[string]string{}
+			}
+			st.Enrichments[key] = resp.EnrichmentItem.ID
+		}
+	}
+
+	if spec.Cover != "" {
+		cover := spec.Cover
+		if id, ok := st.Files[cover]; ok {
+			cover = id
+		}
+		if cover != album.CoverPhotoMediaItemID {
+			act(AlbumPlanCover, spec.Cover)
+			if apply {
+				album.CoverPhotoMediaItemID = cover
+				if _, err := Albums.Patch(client, album, AlbumCoverPhotoMediaItemIDField); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	shared := album.ShareInfo.ShareToken != "" || album.ShareInfo.ShareableURL != ""
+	switch {
+	case spec.Share != nil && (!shared || album.ShareInfo.SharedAlbumOptions != *spec.Share):
+		act(AlbumPlanShare, fmt.Sprintf("collaborative=%t commentable=%t", spec.Share.IsCollaborative, spec.Share.IsCommentable))
+		if apply {
+			if _, err := Albums.Share(client, album.ID, AlbumsShareRequest{SharedAlbumOptions: *spec.Share}); err != nil {
+				return err
+			}
+		}
+	case spec.Share == nil && spec.Unshare && shared:
+		act(AlbumPlanUnshare, "")
+		if apply {
+			if err := Albums.Unshare(client, album.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// uploadIntoAlbum uploads a file into the album without removing it.
+func uploadIntoAlbum(client *http.Client, albumID string, filePath string) (MediaItem, error) {
+	token, err := UploadingMedia.UploadMedia(client, filePath, filepath.Base(filePath))
+	if err != nil {
+		return MediaItem{}, err
+	}
+	resp, err := MediaItems.BatchCreate(client, MediaItemsBatchCreateRequest{
+		AlbumID:       albumID,
+		NewMediaItems: []NewMediaItem{{SimpleMediaItem: SimpleMediaItem{UploadToken: token}}},
+	})
+	if err != nil {
+		return MediaItem{}, err
+	}
+	if len(resp.NewMediaItemResults) != 1 || resp.NewMediaItemResults[0].Status.Message != "OK" {
+		return MediaItem{}, errors.New("NewMediaItemResult.Status.Message is not \"OK\": " + filePath)
+	}
+	return resp.NewMediaItemResults[0].MediaItem, nil
+}
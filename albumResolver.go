@@ -0,0 +1,173 @@
+package gphotos
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MissingAlbumPolicy decides what AlbumResolver.Resolve does when no album has the title.
+type MissingAlbumPolicy int
+
+// Policies for missing titles.
+const (
+	// MissingAlbumError returns ErrAlbumNotFound.
+	MissingAlbumError MissingAlbumPolicy = iota
+
+	// MissingAlbumCreate creates an album with the title.
+	MissingAlbumCreate
+)
+
+// DuplicateAlbumPolicy decides which album AlbumResolver.Resolve returns when several albums have the title.
+type DuplicateAlbumPolicy int
+
+// Policies for duplicate titles.
+const (
+	// DuplicateAlbumFirst returns the first album listed, owned albums before shared albums.
+	DuplicateAlbumFirst DuplicateAlbumPolicy = iota
+
+	// DuplicateAlbumNewest returns the album containing the most recently created media item.
+	// The media items of each candidate are searched, so it is slower than the other policies.
+	DuplicateAlbumNewest
+
+	// DuplicateAlbumWritable only considers albums the app can add media items to, and returns the first of them.
+	// If none is writable, the title is treated as missing.
+	DuplicateAlbumWritable
+
+	// DuplicateAlbumFail returns a *DuplicateAlbumError.
+	DuplicateAlbumFail
+)
+
+// ErrAlbumNotFound is returned by AlbumResolver.Resolve when no album has the title and MissingAlbumError is set.
+var ErrAlbumNotFound = errors.New("album not found")
+
+// DuplicateAlbumError is returned by AlbumResolver.Resolve when several albums have the title and DuplicateAlbumFail is set.
+type DuplicateAlbumError struct {
+	Title  string
+	Albums []Album
+}
+
+func (err *DuplicateAlbumError) Error() string {
+	return strconv.Itoa(len(err.Albums)) + " albums are titled " + strconv.Quote(err.Title)
+}
+
+// AlbumResolverOptions is options of NewAlbumResolver function.
+type AlbumResolverOptions struct {
+	Missing   MissingAlbumPolicy
+	Duplicate DuplicateAlbumPolicy
+
+	// ExcludeSharedAlbums skips the shared albums the user has joined.
+	ExcludeSharedAlbums bool
+}
+
+// AlbumResolver finds albums by title. Owned and shared albums are listed once with the largest page size
+// and kept in an in-memory index. It is safe for concurrent use.
+type AlbumResolver struct {
+	client  *http.Client
+	options AlbumResolverOptions
+
+	mu      sync.Mutex
+	loaded  bool
+	byTitle map[string][]Album
+}
+
+// NewAlbumResolver returns an AlbumResolver with the options.
+func NewAlbumResolver(client *http.Client, options AlbumResolverOptions) *AlbumResolver {
+	return &AlbumResolver{client: client, options: options}
+}
+
+// Refresh is a method that lists the albums again.
+func (resolver *AlbumResolver) Refresh() error {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+	return resolver.load()
+}
+
+// load must be called with the lock held.
+func (resolver *AlbumResolver) load() error {
+	byTitle := map[string][]Album{}
+	seen := map[string]bool{}
+	add := func(album Album) error {
+		if !seen[album.ID] {
+			seen[album.ID] = true
+			byTitle[album.Title] = append(byTitle[album.Title], album)
+		}
+		return nil
+	}
+	if err := listAllAlbums(resolver.client, add); err != nil {
+		return err
+	}
+	if !resolver.options.ExcludeSharedAlbums {
+		if err := listAllSharedAlbums(resolver.client, add); err != nil {
+			return err
+		}
+	}
+	resolver.byTitle = byTitle
+	resolver.loaded = true
+	return nil
+}
+
+// Resolve is a method that returns the album with the title according to the policies of the AlbumResolver.
+func (resolver *AlbumResolver) Resolve(title string) (Album, error) {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+	if !resolver.loaded {
+		if err := resolver.load(); err != nil {
+			return Album{}, err
+		}
+	}
+
+	candidates := resolver.byTitle[title]
+	if resolver.options.Duplicate == DuplicateAlbumWritable {
+		var writable []Album
+		for _, album := range candidates {
+			if album.IsWriteable {
+				writable = append(writable, album)
+			}
+		}
+		candidates = writable
+	}
+
+	switch {
+	case len(candidates) == 0:
+		if resolver.options.Missing != MissingAlbumCreate {
+			return Album{}, ErrAlbumNotFound
+		}
+		album, err := createAlbum(resolver.client, title)
+		if err != nil {
+			return Album{}, err
+		}
+		resolver.byTitle[title] = append(resolver.byTitle[title], album)
+		return album, nil
+	case len(candidates) == 1:
+		return candidates[0], nil
+	}
+
+	switch resolver.options.Duplicate {
+	case DuplicateAlbumFail:
+		return Album{}, &DuplicateAlbumError{Title: title, Albums: append([]Album(nil), candidates...)}
+	case DuplicateAlbumNewest:
+		return newestAlbum(resolver.client, candidates)
+	}
+	return candidates[0], nil
+}
+
+// newestAlbum returns the album containing the most recently created media item.
+func newestAlbum(client *http.Client, albums []Album) (Album, error) {
+	newest := albums[0]
+	var newestTime time.Time
+	for _, album := range albums {
+		err := searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: album.ID}, func(item MediaItem) error {
+			if t := item.MediaMetadata.CreationTime; t.After(newestTime) {
+				newest, newestTime = album, t
+			}
+			return nil
+		})
+		if err != nil {
+			return Album{}, err
+		}
+	}
+	return newest, nil
+}
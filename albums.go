@@ -3,10 +3,12 @@ package gphotos
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 // Albums is the only instance of AlbumsRequests(https://godoc.org/github.com/Q-Brains/gphotos#AlbumsRequests).
@@ -42,6 +44,10 @@ type AlbumsRequests interface {
 	// Source: https://developers.google.com/photos/library/reference/rest/v1/albums/list
 	List(client *http.Client, queries ...ListQuery) (AlbumsListResponse, error)
 
+	// Patch is a method that updates the album with the specified `id`. Only the fields in updateMask are updated.
+	// Source: https://developers.google.com/photos/library/reference/rest/v1/albums/patch
+	Patch(client *http.Client, album Album, updateMask ...AlbumField) (AlbumsPatchResponse, error)
+
 	// Share is a method that marks an album as shared and accessible to other users.
 	// Source: https://developers.google.com/photos/library/reference/rest/v1/albums/share
 	Share(client *http.Client, albumID string, request AlbumsShareRequest) (AlbumsShareResponse, error)
@@ -64,14 +70,14 @@ func (albums albumsRequests) baseURL() string {
 // Album represents an album in Google Photos.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/albums#resource:-album
 type Album struct {
-	ID                    string      `json:"id,omitempty"`
-	Title                 string      `json:"title,omitempty"`
-	ProductURL            string      `json:"productUrl,omitempty"`
-	IsWriteable           bool        `json:"isWriteable,omitempty"`
-	ShareInfo             ShareInfo   `json:"shareInfo,omitempty"`
-	MediaItemsCount       json.Number `json:"mediaItemsCount,omitempty"`
-	CoverPhotoBaseURL     string      `json:"coverPhotoBaseUrl,omitempty"`
-	CoverPhotoMediaItemID string      `json:"coverPhotoMediaItemId,omitempty"`
+	ID                    string    `json:"id,omitempty"`
+	Title                 string    `json:"title,omitempty"`
+	ProductURL            string    `json:"productUrl,omitempty"`
+	IsWriteable           bool      `json:"isWriteable,omitempty"`
+	ShareInfo             ShareInfo `json:"shareInfo,omitempty"`
+	MediaItemsCount       int64     `json:"mediaItemsCount,string,omitempty"`
+	CoverPhotoBaseURL     string    `json:"coverPhotoBaseUrl,omitempty"`
+	CoverPhotoMediaItemID string    `json:"coverPhotoMediaItemId,omitempty"`
 }
 
 // ShareInfo represents information about albums that are shared.
@@ -140,6 +146,25 @@ type NewEnrichmentItem struct {
 	MapEnrichment      MapEnrichment      `json:"mapEnrichment,omitempty"`
 }
 
+// MarshalJSON omits the enrichments that are not set, since the API expects exactly one of them.
+func (item NewEnrichmentItem) MarshalJSON() ([]byte, error) {
+	var aux struct {
+		TextEnrichment     *TextEnrichment     `json:"textEnrichment,omitempty"`
+		LocationEnrichment *LocationEnrichment `json:"locationEnrichment,omitempty"`
+		MapEnrichment      *MapEnrichment      `json:"mapEnrichment,omitempty"`
+	}
+	if item.TextEnrichment != (TextEnrichment{}) {
+		aux.TextEnrichment = &item.TextEnrichment
+	}
+	if item.LocationEnrichment != (LocationEnrichment{}) {
+		aux.LocationEnrichment = &item.LocationEnrichment
+	}
+	if item.MapEnrichment != (MapEnrichment{}) {
+		aux.MapEnrichment = &item.MapEnrichment
+	}
+	return json.Marshal(aux)
+}
+
 // TextEnrichment represents an enrichment containing text.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/albums/addEnrichment#textenrichment
 type TextEnrichment struct {
@@ -162,8 +187,8 @@ type Location struct {
 // LatLng represents a latitude/longitude pair.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/albums/addEnrichment#latlng
 type LatLng struct {
-	Latitude  int `json:"latitude,omitempty"`
-	Longitude int `json:"longitude,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
 }
 
 // MapEnrichment represents an enrichment containing a map, showing origin and destination locations.
@@ -350,13 +375,76 @@ func PageToken(token string) ListQuery {
 	}
 }
 
-// ExcludeNonAppCreatedData is a function to pass a boolean value to whether to exclude the value created by App to Albums.List, MediaItems.List and SharedAlbums.List.
+// ExcludeNonAppCreatedData is a function to pass a boolean value to whether to exclude the value created by App to Albums.List and SharedAlbums.List.
+// MediaItems.List doesn't accept it; use Filters.ExcludeNonAppCreatedData with MediaItems.Search instead.
 func ExcludeNonAppCreatedData(flag bool) ListQuery {
 	return func(v *url.Values) {
 		v.Add("excludeNonAppCreatedData", strconv.FormatBool(flag))
 	}
 }
 
+// - patch
+
+func (albums albumsRequests) Patch(client *http.Client, album Album, updateMask ...AlbumField) (AlbumsPatchResponse, error) {
+	if len(updateMask) == 0 {
+		return AlbumsPatchResponse{}, errors.New("updateMask of Albums.Patch is empty")
+	}
+	body := map[string]string{}
+	fields := make([]string, 0, len(updateMask))
+	for _, field := range updateMask {
+		switch field {
+		case AlbumTitleField:
+			body[string(field)] = album.Title
+		case AlbumCoverPhotoMediaItemIDField:
+			body[string(field)] = album.CoverPhotoMediaItemID
+		default:
+			return AlbumsPatchResponse{}, errors.New("unsupported field of Albums.Patch: " + string(field))
+		}
+		fields = append(fields, string(field))
+	}
+	outputJSON, err := json.Marshal(body)
+	if err != nil {
+		return AlbumsPatchResponse{}, err
+	}
+	req, err := http.NewRequest("PATCH", albums.baseURL()+"/"+album.ID, bytes.NewBuffer(outputJSON))
+	if err != nil {
+		return AlbumsPatchResponse{}, err
+	}
+	req.URL.RawQuery = url.Values{"updateMask": {strings.Join(fields, ",")}}.Encode()
+	resp, err := client.Do(req)
+	if err != nil {
+		return AlbumsPatchResponse{}, err
+	}
+	defer resp.Body.Close()
+	e := RequestError(resp)
+	if e != nil {
+		return AlbumsPatchResponse{}, e
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return AlbumsPatchResponse{}, err
+	}
+	var response AlbumsPatchResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return AlbumsPatchResponse{}, err
+	}
+	return response, nil
+}
+
+// AlbumField is a field of Album that can be updated by Albums.Patch.
+// Source: https://developers.google.com/photos/library/reference/rest/v1/albums/patch#query-parameters
+type AlbumField string
+
+// Fields of Album that can be updated by Albums.Patch.
+const (
+	AlbumTitleField                 AlbumField = "title"
+	AlbumCoverPhotoMediaItemIDField AlbumField = "coverPhotoMediaItemId"
+)
+
+// AlbumsPatchResponse is the body returned by the Albums.Patch method.
+// Source: https://developers.google.com/photos/library/reference/rest/v1/albums/patch#response-body
+type AlbumsPatchResponse Album
+
 // - share
 
 func (albums albumsRequests) Share(client *http.Client, albumID string, request AlbumsShareRequest) (AlbumsShareResponse, error) {
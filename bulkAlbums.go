@@ -0,0 +1,133 @@
+package gphotos
+
+import (
+	"net/http"
+	"sync"
+)
+
+// BulkOptions is options of BulkAddMediaItems and BulkRemoveMediaItems functions.
+type BulkOptions struct {
+	// Concurrency is the number of chunks sent at the same time. The default is 4.
+	Concurrency int
+
+	// SkipUnchanged searches the album first and skips IDs already present when adding, or absent when removing.
+	SkipUnchanged bool
+}
+
+// BulkResult is the result of BulkAddMediaItems and BulkRemoveMediaItems functions.
+type BulkResult struct {
+	Succeeded []string
+	Skipped   []string
+	Failed    []BulkFailure
+}
+
+// BulkFailure represents a media item ID that couldn't be added or removed.
+type BulkFailure struct {
+	MediaItemID string
+	Err         error
+}
+
+// BulkAddMediaItems adds any number of media items to an album with Albums.BatchAddMediaItems, in chunks of 50.
+// When a chunk is rejected as an invalid argument, its IDs are retried one by one so that the result tells which IDs failed and why,
+// for example media items that were not created by the app. Other errors, such as authorization, quota, server or network errors,
+// fail every ID of the chunk without retrying.
+// The returned error is only set when the album can't be searched for SkipUnchanged.
+func BulkAddMediaItems(client *http.Client, albumID string, mediaItemIDs []string, options BulkOptions) (BulkResult, error) {
+	return bulkAlbumMembership(client, albumID, mediaItemIDs, options, true, func(ids []string) error {
+		return Albums.BatchAddMediaItems(client, albumID, AlbumsBatchAddMediaItemsRequest{MediaItemIDs: ids})
+	})
+}
+
+// BulkRemoveMediaItems removes any number of media items from an album with Albums.BatchRemoveMediaItems, in chunks of 50.
+// Failures are isolated and reported in the same way as BulkAddMediaItems.
+func BulkRemoveMediaItems(client *http.Client, albumID string, mediaItemIDs []string, options BulkOptions) (BulkResult, error) {
+	return bulkAlbumMembership(client, albumID, mediaItemIDs, options, false, func(ids []string) error {
+		return Albums.BatchRemoveMediaItems(client, albumID, AlbumsBatchRemoveMediaItemsRequest{MediaItemIDs: ids})
+	})
+}
+
+func bulkAlbumMembership(client *http.Client, albumID string, mediaItemIDs []string, options BulkOptions, add bool, send func([]string) error) (BulkResult, error) {
+	var result BulkResult
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+
+	var present map[string]bool
+	if options.SkipUnchanged {
+		present = map[string]bool{}
+		err := searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: albumID}, func(item MediaItem) error {
+			present[item.ID] = true
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	seen := map[string]bool{}
+	var ids []string
+	for _, id := range mediaItemIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if present != nil && present[id] == add {
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	var chunks [][]string
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		chunks = append(chunks, ids[:n])
+		ids = ids[n:]
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, options.Concurrency)
+	for _, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var succeeded []string
+			var failed []BulkFailure
+			if err := send(chunk); err == nil {
+				succeeded = chunk
+			} else if len(chunk) == 1 || !isInvalidArgument(err) {
+				for _, id := range chunk {
+					failed = append(failed, BulkFailure{MediaItemID: id, Err: err})
+				}
+			} else {
+				for _, id := range chunk {
+					if err := send([]string{id}); err != nil {
+						failed = append(failed, BulkFailure{MediaItemID: id, Err: err})
+					} else {
+						succeeded = append(succeeded, id)
+					}
+				}
+			}
+
+			mu.Lock()
+			result.Succeeded = append(result.Succeeded, succeeded...)
+			result.Failed = append(result.Failed, failed...)
+			mu.Unlock()
+		}(chunk)
+	}
+	wg.Wait()
+	return result, nil
+}
+
+// isInvalidArgument reports whether the API rejected the request itself, rather than failing to process it.
+func isInvalidArgument(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && (apiErr.StatusCode == http.StatusBadRequest || apiErr.Response.Error.Status == "INVALID_ARGUMENT")
+}
@@ -0,0 +1,281 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Catalog is a snapshot cache of the metadata of MediaItems, held in memory and rewritten as a whole to a JSON file
+// on each Sync and Save. It is not an incremental store: see Catalog.Sync for what a sync that isn't full misses.
+// Album membership is kept in an AlbumIndex stored next to the file, with ".albums" before its extension.
+// Use OpenCatalog to create a Catalog. It is safe for concurrent use.
+type Catalog struct {
+	filePath string
+	albums   *AlbumIndex
+
+	mu    sync.RWMutex
+	data  catalogData
+	index map[string]map[string]bool // token -> media item IDs
+}
+
+type catalogData struct {
+	SyncedAt   time.Time            `json:"syncedAt"`
+	MediaItems map[string]MediaItem `json:"mediaItems"`
+}
+
+// CatalogSyncOptions is options of Catalog.Sync method.
+type CatalogSyncOptions struct {
+	// Full searches every album, lists every media item with MediaItems.List and removes the media items that are gone.
+	// The first Sync of a Catalog is always full.
+	Full bool
+}
+
+// CatalogSyncResult is the result of Catalog.Sync method.
+type CatalogSyncResult struct {
+	MediaItemsUpdated int
+	MediaItemsRemoved int
+	AlbumsUpdated     int
+	AlbumsRemoved     int
+}
+
+// CatalogQuery is a query of Catalog.Query method. Empty fields are ignored.
+type CatalogQuery struct {
+	// Text is matched against descriptions and filenames. Every word must match. A word ending with `*` matches as a prefix.
+	Text string
+
+	// AlbumID limits the results to the media items of the album.
+	AlbumID string
+
+	// Filter limits the results with a MediaItemFilter. Filters that can only be checked by the API can't be used.
+	Filter *MediaItemFilter
+}
+
+// OpenCatalog reads a Catalog from filePath. If the file doesn't exist, an empty Catalog is returned.
+func OpenCatalog(filePath string) (*Catalog, error) {
+	ext := filepath.Ext(filePath)
+	albums, err := OpenAlbumIndex(strings.TrimSuffix(filePath, ext) + ".albums" + ext)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &Catalog{
+		filePath: filePath,
+		albums:   albums,
+		data:     catalogData{MediaItems: map[string]MediaItem{}},
+	}
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &catalog.data); err != nil {
+			return nil, err
+		}
+	}
+	if catalog.data.MediaItems == nil {
+		catalog.data.MediaItems = map[string]MediaItem{}
+	}
+	catalog.rebuildIndex()
+	return catalog, nil
+}
+
+// SyncedAt returns the time of the last Sync.
+func (catalog *Catalog) SyncedAt() time.Time {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	return catalog.data.SyncedAt
+}
+
+// Sync is a method that updates the Catalog and its AlbumIndex from the library and saves them.
+// Unless the sync is full, it refreshes the AlbumIndex incrementally and stores the media items that refresh finds:
+// the media items of the albums whose item count or cover changed, and the media items created since the previous Sync.
+// Deleted media items, media items uploaded with older creation dates outside those albums,
+// and descriptions edited on older media items are only picked up by a full Sync.
+func (catalog *Catalog) Sync(client *http.Client, options CatalogSyncOptions) (CatalogSyncResult, error) {
+	var result CatalogSyncResult
+	startedAt := time.Now().UTC()
+
+	catalog.mu.RLock()
+	full := options.Full || catalog.data.SyncedAt.IsZero()
+	catalog.mu.RUnlock()
+
+	items := map[string]MediaItem{}
+	refreshed, err := catalog.albums.refresh(client, AlbumIndexRefreshOptions{Full: full}, func(item MediaItem) error {
+		items[item.ID] = item
+		return nil
+	})
+	result.AlbumsUpdated = refreshed.AlbumsSearched
+	result.AlbumsRemoved = refreshed.AlbumsRemoved
+	if err != nil {
+		return result, err
+	}
+
+	catalog.mu.Lock()
+	if full {
+		for id := range catalog.data.MediaItems {
+			if _, ok := items[id]; !ok {
+				delete(catalog.data.MediaItems, id)
+				result.MediaItemsRemoved++
+			}
+		}
+	}
+	for id, item := range items {
+		catalog.data.MediaItems[id] = item
+	}
+	result.MediaItemsUpdated = len(items)
+	catalog.data.SyncedAt = startedAt
+	catalog.rebuildIndex()
+	catalog.mu.Unlock()
+
+	return result, catalog.Save()
+}
+
+// Save is a method that writes the Catalog and its AlbumIndex to their files.
+func (catalog *Catalog) Save() error {
+	catalog.mu.RLock()
+	b, err := json.Marshal(catalog.data)
+	catalog.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(catalog.filePath+".tmp", b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(catalog.filePath+".tmp", catalog.filePath); err != nil {
+		return err
+	}
+	return catalog.albums.Save()
+}
+
+// MediaItem returns the stored media item.
+func (catalog *Catalog) MediaItem(id string) (MediaItem, bool) {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	item, ok := catalog.data.MediaItems[id]
+	return item, ok
+}
+
+// Albums returns the stored albums sorted by title.
+func (catalog *Catalog) Albums() []Album {
+	return catalog.albums.Albums()
+}
+
+// Query returns the stored media items matching the query, newest first.
+func (catalog *Catalog) Query(query CatalogQuery) ([]MediaItem, error) {
+	if query.Filter != nil {
+		if query.Filter.err != nil {
+			return nil, query.Filter.err
+		}
+		if query.Filter.apiOnly {
+			return nil, errors.New("filters checked only by the API can't be used with Catalog")
+		}
+	}
+
+	var albumItemIDs []string
+	if query.AlbumID != "" {
+		albumItemIDs = catalog.albums.mediaItemIDs(query.AlbumID)
+	}
+
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	var candidates map[string]bool
+	intersect := func(ids map[string]bool) {
+		if candidates == nil {
+			candidates = map[string]bool{}
+			for id := range ids {
+				candidates[id] = true
+			}
+			return
+		}
+		next := map[string]bool{}
+		for id := range candidates {
+			if ids[id] {
+				next[id] = true
+			}
+		}
+		candidates = next
+	}
+	for _, word := range catalogTokens(strings.Replace(query.Text, "*", "\x00", -1)) {
+		ids := map[string]bool{}
+		if strings.HasSuffix(word, "\x00") {
+			prefix := strings.TrimSuffix(word, "\x00")
+			for token, tokenIDs := range catalog.index {
+				if strings.HasPrefix(token, prefix) {
+					for id := range tokenIDs {
+						ids[id] = true
+					}
+				}
+			}
+		} else {
+			ids = catalog.index[word]
+		}
+		intersect(ids)
+	}
+	if query.AlbumID != "" {
+		ids := map[string]bool{}
+		for _, id := range albumItemIDs {
+			ids[id] = true
+		}
+		intersect(ids)
+	}
+
+	var items []MediaItem
+	add := func(item MediaItem) {
+		if query.Filter == nil || query.Filter.Match(item) {
+			items = append(items, item)
+		}
+	}
+	if candidates == nil {
+		for _, item := range catalog.data.MediaItems {
+			add(item)
+		}
+	} else {
+		for id := range candidates {
+			if item, ok := catalog.data.MediaItems[id]; ok {
+				add(item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := items[i].MediaMetadata.CreationTime, items[j].MediaMetadata.CreationTime
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// AlbumsContaining returns the stored albums containing the media item, sorted by title.
+func (catalog *Catalog) AlbumsContaining(mediaItemID string) []Album {
+	return catalog.albums.AlbumsContaining(mediaItemID)
+}
+
+// rebuildIndex must be called with the lock held.
+func (catalog *Catalog) rebuildIndex() {
+	catalog.index = map[string]map[string]bool{}
+	for id, item := range catalog.data.MediaItems {
+		for _, token := range catalogTokens(item.Description + " " + item.Filename) {
+			if catalog.index[token] == nil {
+				catalog.index[token] = map[string]bool{}
+			}
+			catalog.index[token][id] = true
+		}
+	}
+}
+
+// catalogTokens splits text into lower case words. NUL is kept as a word character to mark prefixes.
+func catalogTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r != 0 && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,207 @@
+package gphotos
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Curator is the only instance of CurateMethods(https://godoc.org/github.com/Q-Brains/gphotos#CurateMethods).
+var Curator CurateMethods = curateMethods{}
+
+// CurateMethods is a collection of methods that order albums and choose their cover.
+// The only instance of CurateMethods is Curator(https://godoc.org/github.com/Q-Brains/gphotos#Curator).
+type CurateMethods interface {
+	// Recreate is a method that creates a new album with the title and media items of an album, sorted by options.Order,
+	// and sets its cover with options.Cover.
+	// The API can't reorder an album, and AlbumPosition only places new uploads and enrichments,
+	// so the media items are added in order to the end of the new album one chunk at a time.
+	// Only media items created by the app can be added; the others are returned in Skipped.
+	// The API can't delete albums, so the original album remains.
+	Recreate(client *http.Client, albumID string, options CurateOptions) (CuratedAlbum, error)
+
+	// SetCover is a method that sets the cover of an album to the media item chosen by chooser with Albums.Patch.
+	SetCover(client *http.Client, albumID string, chooser CoverChooser) (Album, error)
+}
+
+// CurateOptions is options of Curator.Recreate method.
+type CurateOptions struct {
+	// Order sorts the media items. The default is ByCreationTime.
+	Order MediaItemOrder
+
+	// Cover chooses the cover of the new album. If nil, the API chooses.
+	Cover CoverChooser
+
+	// EmptyOriginal removes the moved media items from the original album, which must have been created by the app.
+	EmptyOriginal bool
+}
+
+// CuratedAlbum is the result of Curator.Recreate method.
+type CuratedAlbum struct {
+	Original     Album
+	Album        Album
+	MediaItemIDs []string
+	Skipped      []string
+}
+
+// MediaItemOrder reports whether a should be placed before b.
+type MediaItemOrder func(a MediaItem, b MediaItem) bool
+
+// ByCreationTime orders media items from the oldest.
+func ByCreationTime(a MediaItem, b MediaItem) bool {
+	return a.MediaMetadata.CreationTime.Before(b.MediaMetadata.CreationTime)
+}
+
+// ByFilename orders media items by filename, ignoring case.
+func ByFilename(a MediaItem, b MediaItem) bool {
+	return strings.ToLower(a.Filename) < strings.ToLower(b.Filename)
+}
+
+// CoverChooser chooses the cover among the media items of an album. It returns false to leave the cover unchanged.
+type CoverChooser func(client *http.Client, items []MediaItem) (MediaItem, bool, error)
+
+// HighestResolutionCover chooses the photo with the most pixels.
+func HighestResolutionCover(client *http.Client, items []MediaItem) (MediaItem, bool, error) {
+	var cover MediaItem
+	found := false
+	for _, item := range items {
+		if !strings.HasPrefix(item.MimeType, "image/") {
+			continue
+		}
+		if !found || item.MediaMetadata.Megapixels() > cover.MediaMetadata.Megapixels() {
+			cover, found = item, true
+		}
+	}
+	return cover, found, nil
+}
+
+// FavoriteCover chooses the first favorite of the album, or the highest-resolution photo if there is none.
+// The API can't combine an album with filters, so every favorite of the library is searched.
+func FavoriteCover(client *http.Client, items []MediaItem) (MediaItem, bool, error) {
+	favorites := map[string]bool{}
+	request, err := NewSearch().OnlyFavorites().Build()
+	if err != nil {
+		return MediaItem{}, false, err
+	}
+	err = searchAllMediaItems(client, request, func(item MediaItem) error {
+		favorites[item.ID] = true
+		return nil
+	})
+	if err != nil {
+		return MediaItem{}, false, err
+	}
+	for _, item := range items {
+		if favorites[item.ID] {
+			return item, true, nil
+		}
+	}
+	return HighestResolutionCover(client, items)
+}
+
+type curateMethods struct{}
+
+func (curator curateMethods) Recreate(client *http.Client, albumID string, options CurateOptions) (CuratedAlbum, error) {
+	if options.Order == nil {
+		options.Order = ByCreationTime
+	}
+	resp, err := Albums.Get(client, albumID)
+	if err != nil {
+		return CuratedAlbum{}, err
+	}
+	result := CuratedAlbum{Original: Album(resp)}
+	if options.EmptyOriginal && !result.Original.IsWriteable {
+		return result, errors.New("album " + result.Original.Title + " was not created by the app")
+	}
+
+	var items []MediaItem
+	err = searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: albumID}, func(item MediaItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	appCreated, err := appCreatedMediaItemIDs(client)
+	if err != nil {
+		return result, err
+	}
+
+	var movable []MediaItem
+	for _, item := range items {
+		if appCreated[item.ID] {
+			movable = append(movable, item)
+		} else {
+			result.Skipped = append(result.Skipped, item.ID)
+		}
+	}
+	sort.SliceStable(movable, func(i, j int) bool { return options.Order(movable[i], movable[j]) })
+	for _, item := range movable {
+		result.MediaItemIDs = append(result.MediaItemIDs, item.ID)
+	}
+
+	result.Album, err = createAlbum(client, result.Original.Title)
+	if err != nil {
+		return result, err
+	}
+	// Chunks are sent one after another so that they are appended in order.
+	for ids := result.MediaItemIDs; len(ids) > 0; {
+		n := len(ids)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		if err := Albums.BatchAddMediaItems(client, result.Album.ID, AlbumsBatchAddMediaItemsRequest{MediaItemIDs: ids[:n]}); err != nil {
+			return result, err
+		}
+		ids = ids[n:]
+	}
+
+	if options.Cover != nil {
+		cover, ok, err := options.Cover(client, movable)
+		if err != nil {
+			return result, err
+		}
+		if ok {
+			result.Album.CoverPhotoMediaItemID = cover.ID
+			patched, err := Albums.Patch(client, result.Album, AlbumCoverPhotoMediaItemIDField)
+			if err != nil {
+				return result, err
+			}
+			result.Album = Album(patched)
+		}
+	}
+
+	if options.EmptyOriginal && len(result.MediaItemIDs) > 0 {
+		bulk, _ := BulkRemoveMediaItems(client, albumID, result.MediaItemIDs, BulkOptions{})
+		if len(bulk.Failed) > 0 {
+			return result, errors.New("removing " + bulk.Failed[0].MediaItemID + " from " + result.Original.Title + ": " + bulk.Failed[0].Err.Error())
+		}
+	}
+	return result, nil
+}
+
+func (curator curateMethods) SetCover(client *http.Client, albumID string, chooser CoverChooser) (Album, error) {
+	resp, err := Albums.Get(client, albumID)
+	if err != nil {
+		return Album{}, err
+	}
+	album := Album(resp)
+	var items []MediaItem
+	err = searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: albumID}, func(item MediaItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return album, err
+	}
+	cover, ok, err := chooser(client, items)
+	if err != nil || !ok {
+		return album, err
+	}
+	album.CoverPhotoMediaItemID = cover.ID
+	patched, err := Albums.Patch(client, album, AlbumCoverPhotoMediaItemIDField)
+	if err != nil {
+		return album, err
+	}
+	return Album(patched), nil
+}
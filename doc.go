@@ -7,6 +7,13 @@ Authorization
 API Refercence (https://developers.google.com/photos/library/guides/authentication-authorization)
 Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Auth)
 
+Errors
+
+Methods that call the API return an *APIError when the API responds with a status code other than 2xx.
+RequestError used to print the ErrorResponse and return nil, so such responses were decoded as empty results;
+callers that relied on that now receive the error.
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#APIError)
+
 Resource: albums
 
 API Refercence (https://developers.google.com/photos/library/reference/rest/v1/albums)
@@ -30,5 +37,92 @@ Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Uploadi
 Customize Uploader
 
 Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Uploader)
+
+Watch folder
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Watcher)
+
+Google Takeout
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Takeout)
+
+Library manifest
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#LibraryManifest)
+
+Album mirror
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Mirror)
+
+Library snapshots
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Snapshots)
+
+Search builder and text queries
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#SearchBuilder)
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Query)
+
+Client-side filters
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#MediaItemFilter)
+
+Offline catalog
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Catalog)
+
+Library statistics
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Reports)
+
+Album membership index
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#AlbumIndex)
+
+Smart albums
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#SmartAlbums)
+
+Video processing
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#WaitForVideoProcessing)
+
+Album lookup by title
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#AlbumResolver)
+
+Album cache
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#AlbumCache)
+
+Bulk album membership
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#BulkAddMediaItems)
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#BulkRemoveMediaItems)
+
+Album storyboards
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Storyboards)
+
+Album manifests
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#AlbumManifests)
+
+Geotagged enrichments
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#ReadGeoTag)
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#TripMapEnrichment)
+
+Album merge and split
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Reorganizer)
+
+Account migration
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Migrator)
+
+Album curation
+
+Q-Brains/gphotos instance (https://godoc.org/github.com/Q-Brains/gphotos#Curator)
 */
 package gphotos
@@ -0,0 +1,192 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The enum values are sent and received as their names in the API.
+
+var contentCategoryNames = []string{
+	"NONE",
+	"LANDSCAPES",
+	"RECEIPTS",
+	"CITYSCAPES",
+	"LANDMARKS",
+	"SELFIES",
+	"PEOPLE",
+	"PETS",
+	"WEDDINGS",
+	"BIRTHDAYS",
+	"DOCUMENTS",
+	"TRAVEL",
+	"ANIMALS",
+	"FOOD",
+	"SPORT",
+	"NIGHT",
+	"PERFORMANCES",
+	"WHITEBOARDS",
+	"SCREENSHOTS",
+	"UTILITY",
+	"ARTS",
+	"CRAFTS",
+	"FASHION",
+	"HOUSES",
+	"GARDENS",
+	"FLOWERS",
+	"HOLIDAYS",
+}
+
+var mediaTypeNames = []string{
+	"ALL_MEDIA",
+	"VIDEO",
+	"PHOTO",
+}
+
+var featureNames = []string{
+	"NONE",
+	"FAVORITES",
+}
+
+var positionTypeNames = []string{
+	"POSITION_TYPE_UNSPECIFIED",
+	"FIRST_IN_ALBUM",
+	"LAST_IN_ALBUM",
+	"AFTER_MEDIA_ITEM",
+	"AFTER_ENRICHMENT_ITEM",
+}
+
+var videoProcessingStatusNames = []string{
+	"UNSPECIFIED",
+	"PROCESSING",
+	"READY",
+	"FAILED",
+}
+
+func enumString(names []string, typeName string, value int) string {
+	if value < 0 || value >= len(names) {
+		return fmt.Sprintf("%s(%d)", typeName, value)
+	}
+	return names[value]
+}
+
+func enumMarshalJSON(names []string, typeName string, value int) ([]byte, error) {
+	if value < 0 || value >= len(names) {
+		return nil, fmt.Errorf("unknown %s %d", typeName, value)
+	}
+	return json.Marshal(names[value])
+}
+
+func enumUnmarshalJSON(names []string, typeName string, b []byte) (int, error) {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return 0, err
+	}
+	for i, n := range names {
+		if n == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", typeName, name)
+}
+
+// String returns the name of the ContentCategory used by the API, such as "LANDSCAPES".
+func (category ContentCategory) String() string {
+	return enumString(contentCategoryNames, "ContentCategory", int(category))
+}
+
+// MarshalJSON encodes the ContentCategory as its name.
+func (category ContentCategory) MarshalJSON() ([]byte, error) {
+	return enumMarshalJSON(contentCategoryNames, "ContentCategory", int(category))
+}
+
+// UnmarshalJSON decodes the ContentCategory from its name.
+func (category *ContentCategory) UnmarshalJSON(b []byte) error {
+	v, err := enumUnmarshalJSON(contentCategoryNames, "ContentCategory", b)
+	if err != nil {
+		return err
+	}
+	*category = ContentCategory(v)
+	return nil
+}
+
+// String returns the name of the MediaType used by the API, such as "VIDEO".
+func (mediaType MediaType) String() string {
+	return enumString(mediaTypeNames, "MediaType", int(mediaType))
+}
+
+// MarshalJSON encodes the MediaType as its name.
+func (mediaType MediaType) MarshalJSON() ([]byte, error) {
+	return enumMarshalJSON(mediaTypeNames, "MediaType", int(mediaType))
+}
+
+// UnmarshalJSON decodes the MediaType from its name.
+func (mediaType *MediaType) UnmarshalJSON(b []byte) error {
+	v, err := enumUnmarshalJSON(mediaTypeNames, "MediaType", b)
+	if err != nil {
+		return err
+	}
+	*mediaType = MediaType(v)
+	return nil
+}
+
+// String returns the name of the Feature used by the API, such as "FAVORITES".
+func (feature Feature) String() string {
+	return enumString(featureNames, "Feature", int(feature))
+}
+
+// MarshalJSON encodes the Feature as its name.
+func (feature Feature) MarshalJSON() ([]byte, error) {
+	return enumMarshalJSON(featureNames, "Feature", int(feature))
+}
+
+// UnmarshalJSON decodes the Feature from its name.
+func (feature *Feature) UnmarshalJSON(b []byte) error {
+	v, err := enumUnmarshalJSON(featureNames, "Feature", b)
+	if err != nil {
+		return err
+	}
+	*feature = Feature(v)
+	return nil
+}
+
+// String returns the name of the PositionType used by the API, such as "LAST_IN_ALBUM".
+func (position PositionType) String() string {
+	return enumString(positionTypeNames, "PositionType", int(position))
+}
+
+// MarshalJSON encodes the PositionType as its name.
+func (position PositionType) MarshalJSON() ([]byte, error) {
+	return enumMarshalJSON(positionTypeNames, "PositionType", int(position))
+}
+
+// UnmarshalJSON decodes the PositionType from its name.
+func (position *PositionType) UnmarshalJSON(b []byte) error {
+	v, err := enumUnmarshalJSON(positionTypeNames, "PositionType", b)
+	if err != nil {
+		return err
+	}
+	*position = PositionType(v)
+	return nil
+}
+
+// String returns the name of the VideoProcessingStatus used by the API, such as "READY".
+func (status VideoProcessingStatus) String() string {
+	return enumString(videoProcessingStatusNames, "VideoProcessingStatus", int(status))
+}
+
+// MarshalJSON encodes the VideoProcessingStatus as its name.
+func (status VideoProcessingStatus) MarshalJSON() ([]byte, error) {
+	return enumMarshalJSON(videoProcessingStatusNames, "VideoProcessingStatus", int(status))
+}
+
+// UnmarshalJSON decodes the VideoProcessingStatus from its name.
+// Statuses unknown to this package are decoded as VideoProcessingStatusUnspecified, so that new statuses don't break decoding of MediaItems.
+func (status *VideoProcessingStatus) UnmarshalJSON(b []byte) error {
+	v, err := enumUnmarshalJSON(videoProcessingStatusNames, "VideoProcessingStatus", b)
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		return err
+	}
+	*status = VideoProcessingStatus(v)
+	return nil
+}
@@ -0,0 +1,183 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type enumTestCase struct {
+	value interface{}
+	name  string
+	ptr   func() interface{}
+	deref func(interface{}) interface{}
+}
+
+func TestEnumsJSON(t *testing.T) {
+	var cases []enumTestCase
+	for value, name := range map[ContentCategory]string{
+		ContentCategoryNone: "NONE",
+		Landscapes:          "LANDSCAPES",
+		Receipts:            "RECEIPTS",
+		Cityscapes:          "CITYSCAPES",
+		Landmarks:           "LANDMARKS",
+		Selfies:             "SELFIES",
+		People:              "PEOPLE",
+		Pets:                "PETS",
+		Weddings:            "WEDDINGS",
+		Birthdays:           "BIRTHDAYS",
+		Documents:           "DOCUMENTS",
+		Travel:              "TRAVEL",
+		Animals:             "ANIMALS",
+		Food:                "FOOD",
+		Sport:               "SPORT",
+		Night:               "NIGHT",
+		Performances:        "PERFORMANCES",
+		Whiteboards:         "WHITEBOARDS",
+		Screenshots:         "SCREENSHOTS",
+		Utility:             "UTILITY",
+		Arts:                "ARTS",
+		Crafts:              "CRAFTS",
+		Fashion:             "FASHION",
+		Houses:              "HOUSES",
+		Gardens:             "GARDENS",
+		Flowers:             "FLOWERS",
+		Holidays:            "HOLIDAYS",
+	} {
+		cases = append(cases, enumTestCase{value, name,
+			func() interface{} { return new(ContentCategory) },
+			func(p interface{}) interface{} { return *p.(*ContentCategory) }})
+	}
+	for value, name := range map[MediaType]string{
+		AllMedia:  "ALL_MEDIA",
+		VideoType: "VIDEO",
+		PhotoType: "PHOTO",
+	} {
+		cases = append(cases, enumTestCase{value, name,
+			func() interface{} { return new(MediaType) },
+			func(p interface{}) interface{} { return *p.(*MediaType) }})
+	}
+	for value, name := range map[Feature]string{
+		FeatureNone: "NONE",
+		Favorites:   "FAVORITES",
+	} {
+		cases = append(cases, enumTestCase{value, name,
+			func() interface{} { return new(Feature) },
+			func(p interface{}) interface{} { return *p.(*Feature) }})
+	}
+	for value, name := range map[PositionType]string{
+		PositionTypeUnspecified: "POSITION_TYPE_UNSPECIFIED",
+		FirstInAlbum:            "FIRST_IN_ALBUM",
+		LastInAlbum:             "LAST_IN_ALBUM",
+		AfterMediaItem:          "AFTER_MEDIA_ITEM",
+		AfterEnrichmentItem:     "AFTER_ENRICHMENT_ITEM",
+	} {
+		cases = append(cases, enumTestCase{value, name,
+			func() interface{} { return new(PositionType) },
+			func(p interface{}) interface{} { return *p.(*PositionType) }})
+	}
+	for value, name := range map[VideoProcessingStatus]string{
+		VideoProcessingStatusUnspecified: "UNSPECIFIED",
+		Processing:                       "PROCESSING",
+		Ready:                            "READY",
+		Failed:                           "FAILED",
+	} {
+		cases = append(cases, enumTestCase{value, name,
+			func() interface{} { return new(VideoProcessingStatus) },
+			func(p interface{}) interface{} { return *p.(*VideoProcessingStatus) }})
+	}
+
+	for _, c := range cases {
+		b, err := json.Marshal(c.value)
+		if err != nil {
+			t.Errorf("Marshal(%T %v) returned error: %v", c.value, c.value, err)
+			continue
+		}
+		if want := `"` + c.name + `"`; string(b) != want {
+			t.Errorf("Marshal(%T %v) = %s, want %s", c.value, c.value, b, want)
+		}
+		p := c.ptr()
+		if err := json.Unmarshal([]byte(`"`+c.name+`"`), p); err != nil {
+			t.Errorf("Unmarshal(%q) into %T returned error: %v", c.name, p, err)
+			continue
+		}
+		if got := c.deref(p); got != c.value {
+			t.Errorf("Unmarshal(%q) into %T = %v, want %v", c.name, p, got, c.value)
+		}
+	}
+}
+
+func TestEnumsUnknownName(t *testing.T) {
+	var category ContentCategory
+	if err := json.Unmarshal([]byte(`"UNKNOWN"`), &category); err == nil {
+		t.Error("Unmarshal of an unknown ContentCategory returned no error")
+	}
+	if _, err := json.Marshal(ContentCategory(-1)); err == nil {
+		t.Error("Marshal of an out of range ContentCategory returned no error")
+	}
+}
+
+func TestFiltersSlicesJSON(t *testing.T) {
+	filters := Filters{
+		ContentFilter: ContentFilter{
+			IncludedContentCategories: []ContentCategory{Pets, Travel},
+			ExcludedContentCategories: []ContentCategory{Screenshots},
+		},
+		MediaTypeFilter: MediaTypeFilter{MediaTypes: []MediaType{PhotoType}},
+		FeatureFilter:   FeatureFilter{IncludedFeatures: []Feature{Favorites}},
+	}
+	b, err := json.Marshal(filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"dateFilter":{},` +
+		`"contentFilter":{"includedContentCategories":["PETS","TRAVEL"],"excludedContentCategories":["SCREENSHOTS"]},` +
+		`"mediaTypeFilter":{"mediaTypes":["PHOTO"]},` +
+		`"featureFilter":{"includedFeatures":["FAVORITES"]}}`
+	if string(b) != want {
+		t.Errorf("Marshal(Filters) = %s, want %s", b, want)
+	}
+
+	var decoded Filters
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, filters) {
+		t.Errorf("Unmarshal(%s) = %+v, want %+v", b, decoded, filters)
+	}
+}
+
+func TestMediaItemsSearchRequestOmitsEmptyFilters(t *testing.T) {
+	b, err := json.Marshal(MediaItemsSearchRequest{AlbumID: "album", PageSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"albumId":"album","pageSize":100}`; string(b) != want {
+		t.Errorf("Marshal(MediaItemsSearchRequest) = %s, want %s", b, want)
+	}
+
+	b, err = json.Marshal(MediaItemsSearchRequest{Filters: Filters{FeatureFilter: FeatureFilter{IncludedFeatures: []Feature{Favorites}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded["filters"]; !ok {
+		t.Errorf("Marshal(MediaItemsSearchRequest) = %s, want filters", b)
+	}
+}
+
+func TestVideoProcessingStatusUnknown(t *testing.T) {
+	var video Video
+	if err := json.Unmarshal([]byte(`{"fps":30,"status":"NEW_STATUS"}`), &video); err != nil {
+		t.Fatalf("Unmarshal of an unknown status returned error: %v", err)
+	}
+	if video.Status != VideoProcessingStatusUnspecified {
+		t.Errorf("Status = %v, want %v", video.Status, VideoProcessingStatusUnspecified)
+	}
+	if video.FPS != 30 {
+		t.Errorf("FPS = %v, want 30", video.FPS)
+	}
+}
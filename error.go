@@ -2,9 +2,9 @@ package gphotos
 
 import (
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 )
 
 // ErrorResponse represents an error condition.
@@ -16,18 +16,32 @@ type ErrorResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// RequestError receives an error and displays an ErrorResponse.
+// APIError is the error returned when the API responds with a status code other than 2xx.
+type APIError struct {
+	StatusCode int
+	Response   ErrorResponse
+}
+
+func (err *APIError) Error() string {
+	s := "gphotos: " + strconv.Itoa(err.StatusCode) + " " + http.StatusText(err.StatusCode)
+	if err.Response.Error.Status != "" {
+		s += ": " + err.Response.Error.Status
+	}
+	if err.Response.Error.Message != "" {
+		s += ": " + err.Response.Error.Message
+	}
+	return s
+}
+
+// RequestError returns an *APIError when the response has a status code other than 2xx, and nil otherwise.
 func RequestError(resp *http.Response) error {
 	if resp.StatusCode/100 != 2 {
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
-		var response ErrorResponse
-		if err := json.Unmarshal(b, &response); err != nil {
-			return err
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if b, err := ioutil.ReadAll(resp.Body); err == nil {
+			// The body is not always JSON, so the status code alone is reported then.
+			json.Unmarshal(b, &apiErr.Response)
 		}
-		fmt.Println(response)
+		return apiErr
 	}
 	return nil
 }
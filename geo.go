@@ -0,0 +1,265 @@
+package gphotos
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoGeoTag is returned when a file has no EXIF GPS position.
+var ErrNoGeoTag = errors.New("no EXIF GPS position")
+
+// GeoTag is the position and capture time read from the EXIF tags of a file.
+type GeoTag struct {
+	FilePath string
+	LatLng   LatLng
+
+	// Time is the EXIF DateTimeOriginal in UTC, since EXIF doesn't record the time zone. It is zero if absent.
+	Time time.Time
+}
+
+// ReadGeoTag reads the EXIF GPS position of a JPEG or TIFF file. It returns ErrNoGeoTag if the file has none.
+func ReadGeoTag(filePath string) (GeoTag, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return GeoTag{}, err
+	}
+	defer file.Close()
+
+	tiff, err := readExif(bufio.NewReader(file))
+	if err != nil {
+		return GeoTag{}, err
+	}
+	tag, err := parseExifGeoTag(tiff)
+	if err != nil {
+		return GeoTag{}, err
+	}
+	tag.FilePath = filePath
+	return tag, nil
+}
+
+// LocationEnrichmentFromFile returns a location enrichment at the EXIF GPS position of a file.
+func LocationEnrichmentFromFile(filePath string, locationName string) (NewEnrichmentItem, error) {
+	tag, err := ReadGeoTag(filePath)
+	if err != nil {
+		return NewEnrichmentItem{}, err
+	}
+	return NewEnrichmentItem{LocationEnrichment: LocationEnrichment{
+		Location: Location{LocationName: locationName, Latlng: tag.LatLng},
+	}}, nil
+}
+
+// TripMapEnrichment returns a map enrichment from the first to the last geotagged file of a trip.
+// Files are ordered by their EXIF capture time, or kept in the given order if some have none.
+// Files without a GPS position are skipped. It returns ErrNoGeoTag if no file has one.
+func TripMapEnrichment(filePaths []string, originName string, destinationName string) (NewEnrichmentItem, error) {
+	var tags []GeoTag
+	for _, filePath := range filePaths {
+		tag, err := ReadGeoTag(filePath)
+		if err == ErrNoGeoTag {
+			continue
+		}
+		if err != nil {
+			return NewEnrichmentItem{}, err
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		return NewEnrichmentItem{}, ErrNoGeoTag
+	}
+	timed := true
+	for _, tag := range tags {
+		timed = timed && !tag.Time.IsZero()
+	}
+	if timed {
+		sort.SliceStable(tags, func(i, j int) bool { return tags[i].Time.Before(tags[j].Time) })
+	}
+	return NewEnrichmentItem{MapEnrichment: MapEnrichment{
+		Origin:      Location{LocationName: originName, Latlng: tags[0].LatLng},
+		Destination: Location{LocationName: destinationName, Latlng: tags[len(tags)-1].LatLng},
+	}}, nil
+}
+
+// readExif returns the TIFF structure of the EXIF data of a JPEG or TIFF stream.
+func readExif(r *bufio.Reader) ([]byte, error) {
+	head, err := r.Peek(4)
+	if err != nil {
+		return nil, ErrNoGeoTag
+	}
+	if bytes.Equal(head, []byte("II*\x00")) || bytes.Equal(head, []byte("MM\x00*")) {
+		return ioutil.ReadAll(r)
+	}
+	if head[0] != 0xFF || head[1] != 0xD8 {
+		return nil, errors.New("not a JPEG or TIFF file")
+	}
+	r.Discard(2)
+
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return nil, ErrNoGeoTag
+		}
+		if marker[0] != 0xFF {
+			return nil, errors.New("invalid JPEG marker")
+		}
+		// Start of scan or end of image: no more metadata segments.
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, ErrNoGeoTag
+		}
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return nil, errors.New("invalid JPEG segment")
+		}
+		if marker[1] != 0xE1 {
+			if _, err := r.Discard(length); err != nil {
+				return nil, ErrNoGeoTag
+			}
+			continue
+		}
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+type exifIFD struct {
+	tiff  []byte
+	order binary.ByteOrder
+	tags  map[uint16][]byte // tag -> type, count and value or offset
+}
+
+func readExifIFD(tiff []byte, order binary.ByteOrder, offset uint32) (exifIFD, error) {
+	ifd := exifIFD{tiff: tiff, order: order, tags: map[uint16][]byte{}}
+	if int(offset)+2 > len(tiff) {
+		return ifd, errors.New("invalid EXIF IFD offset")
+	}
+	n := int(order.Uint16(tiff[offset:]))
+	entries := tiff[offset+2:]
+	if n*12 > len(entries) {
+		return ifd, errors.New("invalid EXIF IFD")
+	}
+	for i := 0; i < n; i++ {
+		entry := entries[i*12 : i*12+12]
+		ifd.tags[order.Uint16(entry)] = entry[2:]
+	}
+	return ifd, nil
+}
+
+// value returns the bytes of a tag value of the EXIF type with size bytes per component.
+func (ifd exifIFD) value(tag uint16, size int) ([]byte, bool) {
+	entry, ok := ifd.tags[tag]
+	if !ok {
+		return nil, false
+	}
+	n := int(ifd.order.Uint32(entry[2:])) * size
+	if n <= 4 {
+		return entry[6 : 6+n], true
+	}
+	offset := int(ifd.order.Uint32(entry[6:]))
+	if offset < 0 || offset+n > len(ifd.tiff) {
+		return nil, false
+	}
+	return ifd.tiff[offset : offset+n], true
+}
+
+func (ifd exifIFD) long(tag uint16) (uint32, bool) {
+	b, ok := ifd.value(tag, 4)
+	if !ok || len(b) < 4 {
+		return 0, false
+	}
+	return ifd.order.Uint32(b), true
+}
+
+func (ifd exifIFD) ascii(tag uint16) string {
+	b, _ := ifd.value(tag, 1)
+	return strings.TrimRight(string(b), "\x00 ")
+}
+
+// degrees returns the degrees of three rationals: degrees, minutes and seconds.
+func (ifd exifIFD) degrees(tag uint16) (float64, bool) {
+	b, ok := ifd.value(tag, 8)
+	if !ok || len(b) < 24 {
+		return 0, false
+	}
+	var parts [3]float64
+	for i := range parts {
+		num, den := ifd.order.Uint32(b[i*8:]), ifd.order.Uint32(b[i*8+4:])
+		if den == 0 {
+			return 0, false
+		}
+		parts[i] = float64(num) / float64(den)
+	}
+	return parts[0] + parts[1]/60 + parts[2]/3600, true
+}
+
+// EXIF tags used by ReadGeoTag.
+const (
+	exifIFDPointer       = 0x8769
+	exifGPSPointer       = 0x8825
+	exifDateTimeOriginal = 0x9003
+	exifGPSLatitudeRef   = 0x0001
+	exifGPSLatitude      = 0x0002
+	exifGPSLongitudeRef  = 0x0003
+	exifGPSLongitude     = 0x0004
+)
+
+func parseExifGeoTag(tiff []byte) (GeoTag, error) {
+	if len(tiff) < 8 {
+		return GeoTag{}, ErrNoGeoTag
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return GeoTag{}, errors.New("invalid EXIF byte order")
+	}
+	ifd0, err := readExifIFD(tiff, order, order.Uint32(tiff[4:]))
+	if err != nil {
+		return GeoTag{}, err
+	}
+
+	var tag GeoTag
+	if offset, ok := ifd0.long(exifIFDPointer); ok {
+		if exif, err := readExifIFD(tiff, order, offset); err == nil {
+			if t, err := time.Parse("2006:01:02 15:04:05", exif.ascii(exifDateTimeOriginal)); err == nil {
+				tag.Time = t
+			}
+		}
+	}
+
+	offset, ok := ifd0.long(exifGPSPointer)
+	if !ok {
+		return GeoTag{}, ErrNoGeoTag
+	}
+	gps, err := readExifIFD(tiff, order, offset)
+	if err != nil {
+		return GeoTag{}, err
+	}
+	latitude, ok1 := gps.degrees(exifGPSLatitude)
+	longitude, ok2 := gps.degrees(exifGPSLongitude)
+	if !ok1 || !ok2 {
+		return GeoTag{}, ErrNoGeoTag
+	}
+	if gps.ascii(exifGPSLatitudeRef) == "S" {
+		latitude = -latitude
+	}
+	if gps.ascii(exifGPSLongitudeRef) == "W" {
+		longitude = -longitude
+	}
+	tag.LatLng = LatLng{Latitude: latitude, Longitude: longitude}
+	return tag, nil
+}
@@ -0,0 +1,213 @@
+package gphotos
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// LibraryManifest is the only instance of LibraryManifestMethods(https://godoc.org/github.com/Q-Brains/gphotos#LibraryManifestMethods).
+var LibraryManifest LibraryManifestMethods = libraryManifestMethods{}
+
+// LibraryManifestMethods is a collection of methods that dump the library.
+// The only instance of LibraryManifestMethods is LibraryManifest(https://godoc.org/github.com/Q-Brains/gphotos#LibraryManifest).
+type LibraryManifestMethods interface {
+	// Write is a method that streams every Album, album membership and MediaItem of the library to w.
+	// Each record has a "kind" column that is "album", "membership" or "mediaItem".
+	// Records are written as soon as they are received, so memory usage doesn't grow with the size of the library.
+	Write(client *http.Client, w io.Writer, options ManifestOptions) error
+}
+
+// ManifestOptions is options of LibraryManifest.Write method.
+type ManifestOptions struct {
+	// Format is the output format.
+	Format ManifestFormat
+
+	// Columns are the columns to be written. The default is ManifestColumns.
+	// The "kind" column is always written.
+	Columns []string
+
+	// ExcludeSharedAlbums excludes the albums listed only by SharedAlbums.List.
+	ExcludeSharedAlbums bool
+}
+
+// ManifestFormat represents an output format of LibraryManifest.Write method.
+type ManifestFormat int
+
+// Output formats of LibraryManifest.Write method.
+const (
+	// One JSON object per line. Empty columns are omitted.
+	ManifestJSONLines ManifestFormat = iota
+
+	// CSV with a header line.
+	ManifestCSV
+)
+
+// ManifestColumns is the list of all columns supported by LibraryManifest.Write method.
+var ManifestColumns = []string{
+	"kind",
+	"id",
+	"title",
+	"filename",
+	"description",
+	"mimeType",
+	"creationTime",
+	"width",
+	"height",
+	"cameraMake",
+	"cameraModel",
+	"contributorDisplayName",
+	"productUrl",
+	"mediaItemsCount",
+	"isWriteable",
+	"shareableUrl",
+	"isCollaborative",
+	"isCommentable",
+	"isJoined",
+	"isOwned",
+	"albumId",
+	"mediaItemId",
+}
+
+type manifestRecord struct {
+	kind        string
+	album       Album
+	mediaItem   MediaItem
+	albumID     string
+	mediaItemID string
+}
+
+func manifestBool(ok bool, b bool) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatBool(b)
+}
+
+var manifestColumnValues = map[string]func(manifestRecord) string{
+	"kind": func(r manifestRecord) string { return r.kind },
+	"id": func(r manifestRecord) string {
+		if r.kind == "album" {
+			return r.album.ID
+		}
+		return r.mediaItem.ID
+	},
+	"title":                  func(r manifestRecord) string { return r.album.Title },
+	"filename":               func(r manifestRecord) string { return r.mediaItem.Filename },
+	"description":            func(r manifestRecord) string { return r.mediaItem.Description },
+	"mimeType":               func(r manifestRecord) string { return r.mediaItem.MimeType },
+	"creationTime":           func(r manifestRecord) string { return r.mediaItem.MediaMetadata.CreationTimeString() },
+	"width":                  func(r manifestRecord) string { return r.mediaItem.MediaMetadata.WidthString() },
+	"height":                 func(r manifestRecord) string { return r.mediaItem.MediaMetadata.HeightString() },
+	"cameraMake":             func(r manifestRecord) string { return r.mediaItem.MediaMetadata.CameraMake() },
+	"cameraModel":            func(r manifestRecord) string { return r.mediaItem.MediaMetadata.CameraModel() },
+	"contributorDisplayName": func(r manifestRecord) string { return r.mediaItem.ContributorInfo.DisplayName },
+	"productUrl": func(r manifestRecord) string {
+		if r.kind == "album" {
+			return r.album.ProductURL
+		}
+		return r.mediaItem.ProductURL
+	},
+	"mediaItemsCount": func(r manifestRecord) string {
+		if r.kind != "album" {
+			return ""
+		}
+		return strconv.FormatInt(r.album.MediaItemsCount, 10)
+	},
+	"isWriteable":  func(r manifestRecord) string { return manifestBool(r.kind == "album", r.album.IsWriteable) },
+	"shareableUrl": func(r manifestRecord) string { return r.album.ShareInfo.ShareableURL },
+	"isCollaborative": func(r manifestRecord) string {
+		return manifestBool(r.kind == "album", r.album.ShareInfo.SharedAlbumOptions.IsCollaborative)
+	},
+	"isCommentable": func(r manifestRecord) string {
+		return manifestBool(r.kind == "album", r.album.ShareInfo.SharedAlbumOptions.IsCommentable)
+	},
+	"isJoined":    func(r manifestRecord) string { return manifestBool(r.kind == "album", r.album.ShareInfo.IsJoined) },
+	"isOwned":     func(r manifestRecord) string { return manifestBool(r.kind == "album", r.album.ShareInfo.IsOwned) },
+	"albumId":     func(r manifestRecord) string { return r.albumID },
+	"mediaItemId": func(r manifestRecord) string { return r.mediaItemID },
+}
+
+type libraryManifestMethods struct{}
+
+func (manifest libraryManifestMethods) Write(client *http.Client, w io.Writer, options ManifestOptions) error {
+	columns := []string{"kind"}
+	if options.Columns == nil {
+		options.Columns = ManifestColumns
+	}
+	for _, column := range options.Columns {
+		if _, ok := manifestColumnValues[column]; !ok {
+			return errors.New("unknown manifest column: " + column)
+		}
+		if column != "kind" {
+			columns = append(columns, column)
+		}
+	}
+
+	var write func(manifestRecord) error
+	flush := func() error { return nil }
+	switch options.Format {
+	case ManifestJSONLines:
+		enc := json.NewEncoder(w)
+		write = func(record manifestRecord) error {
+			values := map[string]string{}
+			for _, column := range columns {
+				if v := manifestColumnValues[column](record); v != "" {
+					values[column] = v
+				}
+			}
+			return enc.Encode(values)
+		}
+	case ManifestCSV:
+		cw := csv.NewWriter(w)
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		row := make([]string, len(columns))
+		write = func(record manifestRecord) error {
+			for i, column := range columns {
+				row[i] = manifestColumnValues[column](record)
+			}
+			return cw.Write(row)
+		}
+	default:
+		return errors.New("unknown manifest format")
+	}
+
+	written := map[string]bool{}
+	writeAlbum := func(album Album) error {
+		if written[album.ID] {
+			return nil
+		}
+		written[album.ID] = true
+		if err := write(manifestRecord{kind: "album", album: album}); err != nil {
+			return err
+		}
+		return searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: album.ID}, func(item MediaItem) error {
+			return write(manifestRecord{kind: "membership", albumID: album.ID, mediaItemID: item.ID})
+		})
+	}
+	if err := listAllAlbums(client, writeAlbum); err != nil {
+		return err
+	}
+	if !options.ExcludeSharedAlbums {
+		if err := listAllSharedAlbums(client, writeAlbum); err != nil {
+			return err
+		}
+	}
+
+	err := listAllMediaItems(client, func(item MediaItem) error {
+		return write(manifestRecord{kind: "mediaItem", mediaItem: item})
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
@@ -3,9 +3,13 @@ package gphotos
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // MediaItems is the only instance of MediaItemsRequests(https://godoc.org/github.com/Q-Brains/gphotos#MediaItemsRequests).
@@ -33,6 +37,10 @@ type MediaItemsRequests interface {
 	// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/list
 	List(client *http.Client, queries ...ListQuery) (MediaItemsListResponse, error)
 
+	// Patch is a method that updates the media item with the specified `id`. Only the fields in updateMask are updated.
+	// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/patch
+	Patch(client *http.Client, mediaItem MediaItem, updateMask ...MediaItemField) (MediaItemsPatchResponse, error)
+
 	// Search is a method that searches for media items in a user's Google Photos library.
 	// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search
 	Search(client *http.Client, request MediaItemsSearchRequest) (MediaItemsSearchResponse, error)
@@ -64,22 +72,126 @@ type MediaItem struct {
 // MediaMetadata represents metadata for a media item.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#mediametadata
 type MediaMetadata struct {
-	CreationTime string `json:"creationTime,omitempty"`
-	Width        string `json:"width,omitempty"`
-	Height       string `json:"height,omitempty"`
-	Photo        Photo  `json:"photo,omitempty"`
-	Video        Video  `json:"video,omitempty"`
+	CreationTime time.Time `json:"creationTime,omitempty"`
+	Width        int64     `json:"width,string,omitempty"`
+	Height       int64     `json:"height,string,omitempty"`
+	Photo        Photo     `json:"photo,omitempty"`
+	Video        Video     `json:"video,omitempty"`
+}
+
+// CreationTimeString returns CreationTime in the RFC 3339 format used by the API, or "" if it is zero.
+func (metadata MediaMetadata) CreationTimeString() string {
+	if metadata.CreationTime.IsZero() {
+		return ""
+	}
+	return metadata.CreationTime.Format(time.RFC3339Nano)
+}
+
+// MarshalJSON omits CreationTime when it is zero, since omitempty has no effect on a time.Time.
+func (metadata MediaMetadata) MarshalJSON() ([]byte, error) {
+	type plain MediaMetadata
+	if !metadata.CreationTime.IsZero() {
+		return json.Marshal(plain(metadata))
+	}
+	return json.Marshal(struct {
+		plain
+		CreationTime *time.Time `json:"creationTime,omitempty"`
+	}{plain: plain(metadata)})
+}
+
+// WidthString returns Width as a decimal string, or "" if it is zero.
+func (metadata MediaMetadata) WidthString() string {
+	if metadata.Width == 0 {
+		return ""
+	}
+	return strconv.FormatInt(metadata.Width, 10)
+}
+
+// HeightString returns Height as a decimal string, or "" if it is zero.
+func (metadata MediaMetadata) HeightString() string {
+	if metadata.Height == 0 {
+		return ""
+	}
+	return strconv.FormatInt(metadata.Height, 10)
+}
+
+// Megapixels returns the number of pixels in millions.
+func (metadata MediaMetadata) Megapixels() float64 {
+	return float64(metadata.Width) * float64(metadata.Height) / 1e6
+}
+
+// AspectRatio returns Width divided by Height, or 0 if Height is unknown.
+func (metadata MediaMetadata) AspectRatio() float64 {
+	if metadata.Height == 0 {
+		return 0
+	}
+	return float64(metadata.Width) / float64(metadata.Height)
+}
+
+// CameraMake returns the camera make of the photo or the video.
+func (metadata MediaMetadata) CameraMake() string {
+	if metadata.Photo.CameraMake != "" {
+		return metadata.Photo.CameraMake
+	}
+	return metadata.Video.CameraMake
+}
+
+// CameraModel returns the camera model of the photo or the video.
+func (metadata MediaMetadata) CameraModel() string {
+	if metadata.Photo.CameraModel != "" {
+		return metadata.Photo.CameraModel
+	}
+	return metadata.Video.CameraModel
 }
 
 // Photo represents metadata that is specific to a photo, such as, ISO, focal length and exposure time.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#photo
 type Photo struct {
-	CameraMake      string `json:"cameraMake,omitempty"`
-	CameraModel     string `json:"cameraModel,omitempty"`
-	FocalLength     int    `json:"focalLength,omitempty"`
-	ApertureFNumber int    `json:"apertureFNumber,omitempty"`
-	ISOEquivalent   int    `json:"isoEquivalent,omitempty"`
-	ExposureTime    string `json:"exposureTime,omitempty"`
+	CameraMake      string        `json:"cameraMake,omitempty"`
+	CameraModel     string        `json:"cameraModel,omitempty"`
+	FocalLength     float64       `json:"focalLength,omitempty"`
+	ApertureFNumber float64       `json:"apertureFNumber,omitempty"`
+	ISOEquivalent   int           `json:"isoEquivalent,omitempty"`
+	ExposureTime    time.Duration `json:"exposureTime,omitempty"`
+}
+
+// ExposureTimeString returns ExposureTime in the format used by the API, such as "0.008s", or "" if it is zero.
+func (photo Photo) ExposureTimeString() string {
+	if photo.ExposureTime == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(photo.ExposureTime.Seconds(), 'f', -1, 64) + "s"
+}
+
+// MarshalJSON encodes ExposureTime in the format used by the API.
+func (photo Photo) MarshalJSON() ([]byte, error) {
+	type plain Photo
+	return json.Marshal(struct {
+		plain
+		ExposureTime string `json:"exposureTime,omitempty"`
+	}{plain(photo), photo.ExposureTimeString()})
+}
+
+// UnmarshalJSON decodes ExposureTime from the format used by the API.
+func (photo *Photo) UnmarshalJSON(b []byte) error {
+	type plain Photo
+	aux := struct {
+		*plain
+		ExposureTime string `json:"exposureTime,omitempty"`
+	}{plain: (*plain)(photo)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	photo.ExposureTime = 0
+	if aux.ExposureTime == "" {
+		return nil
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSuffix(aux.ExposureTime, "s"), 64)
+	if err != nil {
+		return err
+	}
+	photo.ExposureTime = time.Duration(seconds * float64(time.Second))
+	return nil
 }
 
 // Video represents metadata that is specific to a video, for example, fps and processing status.
@@ -87,7 +199,7 @@ type Photo struct {
 type Video struct {
 	CameraMake  string                `json:"cameraMake,omitempty"`
 	CameraModel string                `json:"cameraModel,omitempty"`
-	FPS         int                   `json:"fps,omitempty"`
+	FPS         float64               `json:"fps,omitempty"`
 	Status      VideoProcessingStatus `json:"status,omitempty"`
 }
 
@@ -95,11 +207,19 @@ type Video struct {
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#videoprocessingstatus
 type VideoProcessingStatus int
 
+// Processing statuses of a video.
 const (
-	unspecified VideoProcessingStatus = iota
-	processing
-	ready
-	failed
+	// Video processing status is unknown.
+	VideoProcessingStatusUnspecified VideoProcessingStatus = iota
+
+	// Video is being processed. The user sees an icon for this video in the Google Photos app; however, it isn't playable yet.
+	Processing
+
+	// Video processing is complete and it is now ready for viewing.
+	Ready
+
+	// Something has gone wrong and the video has failed to process.
+	Failed
 )
 
 // ContributorInfo represents information about the user who added the media item.
@@ -288,6 +408,65 @@ type MediaItemsListResponse struct {
 	NextPageToken string      `json:"nextPageToken,omitempty"`
 }
 
+// - patch
+
+func (mediaItems mediaItemsRequests) Patch(client *http.Client, mediaItem MediaItem, updateMask ...MediaItemField) (MediaItemsPatchResponse, error) {
+	if len(updateMask) == 0 {
+		return MediaItemsPatchResponse{}, errors.New("updateMask of MediaItems.Patch is empty")
+	}
+	body := map[string]string{}
+	fields := make([]string, 0, len(updateMask))
+	for _, field := range updateMask {
+		switch field {
+		case MediaItemDescriptionField:
+			body[string(field)] = mediaItem.Description
+		default:
+			return MediaItemsPatchResponse{}, errors.New("unsupported field of MediaItems.Patch: " + string(field))
+		}
+		fields = append(fields, string(field))
+	}
+	outputJSON, err := json.Marshal(body)
+	if err != nil {
+		return MediaItemsPatchResponse{}, err
+	}
+	req, err := http.NewRequest("PATCH", mediaItems.baseURL()+"/"+mediaItem.ID, bytes.NewBuffer(outputJSON))
+	if err != nil {
+		return MediaItemsPatchResponse{}, err
+	}
+	req.URL.RawQuery = url.Values{"updateMask": {strings.Join(fields, ",")}}.Encode()
+	resp, err := client.Do(req)
+	if err != nil {
+		return MediaItemsPatchResponse{}, err
+	}
+	defer resp.Body.Close()
+	e := RequestError(resp)
+	if e != nil {
+		return MediaItemsPatchResponse{}, e
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return MediaItemsPatchResponse{}, err
+	}
+	var response MediaItemsPatchResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return MediaItemsPatchResponse{}, err
+	}
+	return response, nil
+}
+
+// MediaItemField is a field of MediaItem that can be updated by MediaItems.Patch.
+// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/patch#query-parameters
+type MediaItemField string
+
+// Fields of MediaItem that can be updated by MediaItems.Patch.
+const (
+	MediaItemDescriptionField MediaItemField = "description"
+)
+
+// MediaItemsPatchResponse is the body returned by the MediaItems.Patch method.
+// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/patch#response-body
+type MediaItemsPatchResponse MediaItem
+
 // - search
 
 func (mediaItems mediaItemsRequests) Search(client *http.Client, request MediaItemsSearchRequest) (MediaItemsSearchResponse, error) {
@@ -325,6 +504,18 @@ type MediaItemsSearchRequest struct {
 	Filters   Filters `json:"filters,omitempty"`
 }
 
+// MarshalJSON omits Filters when no filter is set, since the API rejects Filters combined with AlbumID.
+func (request MediaItemsSearchRequest) MarshalJSON() ([]byte, error) {
+	type plain MediaItemsSearchRequest
+	if !request.Filters.isEmpty() {
+		return json.Marshal(plain(request))
+	}
+	return json.Marshal(struct {
+		plain
+		Filters *Filters `json:"filters,omitempty"`
+	}{plain: plain(request)})
+}
+
 // MediaItemsSearchResponse is the body returned by the MediaItems.BatchGet method.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search#response-body
 type MediaItemsSearchResponse struct {
@@ -368,8 +559,8 @@ type DateRange struct {
 // ContentFilter represents the media item returned based on the content type.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search#contentfilter
 type ContentFilter struct {
-	IncludedContentCategories ContentCategory `json:"includedContentCategories,omitempty"`
-	ExcludedContentCategories ContentCategory `json:"excludedContentCategories,omitempty"`
+	IncludedContentCategories []ContentCategory `json:"includedContentCategories,omitempty"`
+	ExcludedContentCategories []ContentCategory `json:"excludedContentCategories,omitempty"`
 }
 
 // ContentCategory represents a set of pre-defined content categories that you can filter on.
@@ -464,7 +655,7 @@ const (
 // MediaTypeFilter represents the type of media items to be returned, for example, videos or photos.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search#mediatypefilter
 type MediaTypeFilter struct {
-	MediaTypes MediaType `json:"mediaTypes,omitempty"`
+	MediaTypes []MediaType `json:"mediaTypes,omitempty"`
 }
 
 // MediaType represents the set of media types that can be searched for.
@@ -488,7 +679,7 @@ const (
 // FeatureFilter represents the features that the media items should have.
 // Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search#featurefilter
 type FeatureFilter struct {
-	IncludedFeatures Feature `json:"includedFeatures,omitempty"`
+	IncludedFeatures []Feature `json:"includedFeatures,omitempty"`
 }
 
 // Feature represents the set of features that you can filter on.
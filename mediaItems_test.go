@@ -0,0 +1,32 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMediaMetadataJSON(t *testing.T) {
+	for _, metadata := range []MediaMetadata{
+		{},
+		{Width: 640, Height: 480},
+		{CreationTime: time.Date(2020, time.March, 1, 12, 30, 0, 0, time.UTC), Photo: Photo{CameraMake: "Pixel", ExposureTime: time.Second / 100}},
+	} {
+		b, err := json.Marshal(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata.CreationTime.IsZero() == strings.Contains(string(b), "creationTime") {
+			t.Errorf("json.Marshal(%+v) = %s, want creationTime only if it is set", metadata, b)
+		}
+		var decoded MediaMetadata
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, metadata) {
+			t.Errorf("json.Unmarshal(%s) = %+v, want %+v", b, decoded, metadata)
+		}
+	}
+}
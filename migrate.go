@@ -0,0 +1,303 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Migrator is the only instance of MigrateMethods(https://godoc.org/github.com/Q-Brains/gphotos#MigrateMethods).
+var Migrator MigrateMethods = migrateMethods{}
+
+// MigrateMethods is a collection of methods that copy a library from one account to another.
+// The only instance of MigrateMethods is Migrator(https://godoc.org/github.com/Q-Brains/gphotos#Migrator).
+type MigrateMethods interface {
+	// Migrate is a method that downloads the media items of the source account and uploads them to the destination account,
+	// recreating albums, their order, covers and the descriptions of media items.
+	// Media items are downloaded with the =d and =dv base URL parameters, and the API strips the GPS location
+	// from such downloads, so the copies have no location.
+	// Source and destination are clients authorized for each account.
+	// Progress is recorded in the mapping file, so an interrupted migration resumes where it stopped.
+	// The API can't read enrichments, so they are only recreated from MigrateOptions.Enrichments.
+	Migrate(source *http.Client, destination *http.Client, options MigrateOptions) (MigrationResult, error)
+}
+
+// MigrateOptions is options of Migrator.Migrate method.
+type MigrateOptions struct {
+	// MappingFile is the JSON file mapping source IDs to destination IDs. It is required.
+	MappingFile string
+
+	// AlbumsOnly skips the media items that are in no album.
+	AlbumsOnly bool
+
+	// ExcludeSharedAlbums skips the shared albums the source user has joined.
+	ExcludeSharedAlbums bool
+
+	// Enrichments are appended to the recreated albums, keyed by source album ID.
+	// They are added once; later runs skip the albums whose enrichments are recorded in the mapping file.
+	Enrichments map[string][]NewEnrichmentItem
+}
+
+// MigrationResult is the result of Migrator.Migrate method.
+type MigrationResult struct {
+	AlbumsCreated     int
+	MediaItemsCopied  int
+	MediaItemsSkipped int
+	EnrichmentsAdded  int
+}
+
+// MigrationMapping is the content of the mapping file of Migrator.Migrate.
+type MigrationMapping struct {
+	Albums      map[string]string `json:"albums"`
+	MediaItems  map[string]string `json:"mediaItems"`
+	Enrichments map[string]bool   `json:"enrichments,omitempty"`
+}
+
+type migrateMethods struct{}
+
+type migration struct {
+	source      *http.Client
+	destination *http.Client
+	options     MigrateOptions
+	mapping     MigrationMapping
+	result      MigrationResult
+	dir         string
+}
+
+func (migrator migrateMethods) Migrate(source *http.Client, destination *http.Client, options MigrateOptions) (MigrationResult, error) {
+	if options.MappingFile == "" {
+		return MigrationResult{}, errors.New("MigrateOptions.MappingFile is required")
+	}
+	m := &migration{source: source, destination: destination, options: options}
+	if err := m.load(); err != nil {
+		return MigrationResult{}, err
+	}
+
+	dir, err := ioutil.TempDir("", "gphotos-migrate")
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	defer os.RemoveAll(dir)
+	m.dir = dir
+
+	var albums []Album
+	seen := map[string]bool{}
+	collect := func(album Album) error {
+		if !seen[album.ID] {
+			seen[album.ID] = true
+			albums = append(albums, album)
+		}
+		return nil
+	}
+	if err := listAllAlbums(source, collect); err != nil {
+		return m.result, err
+	}
+	if !options.ExcludeSharedAlbums {
+		if err := listAllSharedAlbums(source, collect); err != nil {
+			return m.result, err
+		}
+	}
+
+	for _, album := range albums {
+		if err := m.migrateAlbum(album); err != nil {
+			return m.result, err
+		}
+	}
+
+	if !options.AlbumsOnly {
+		batch := &migrationBatch{}
+		err := listAllMediaItems(source, func(item MediaItem) error {
+			return m.copy(batch, "", item)
+		})
+		if err == nil {
+			err = m.flush(batch, "")
+		}
+		if err != nil {
+			return m.result, err
+		}
+	}
+	return m.result, nil
+}
+
+func (m *migration) load() error {
+	b, err := ioutil.ReadFile(m.options.MappingFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &m.mapping); err != nil {
+			return err
+		}
+	}
+	if m.mapping.Albums == nil {
+		m.mapping.Albums = map[string]string{}
+	}
+	if m.mapping.MediaItems == nil {
+		m.mapping.MediaItems = map[string]string{}
+	}
+	if m.mapping.Enrichments == nil {
+		m.mapping.Enrichments = map[string]bool{}
+	}
+	return nil
+}
+
+func (m *migration) save() error {
+	b, err := json.MarshalIndent(m.mapping, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(m.options.MappingFile+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(m.options.MappingFile+".tmp", m.options.MappingFile)
+}
+
+func (m *migration) migrateAlbum(album Album) error {
+	albumID, ok := m.mapping.Albums[album.ID]
+	if !ok {
+		created, err := createAlbum(m.destination, album.Title)
+		if err != nil {
+			return err
+		}
+		albumID = created.ID
+		m.mapping.Albums[album.ID] = albumID
+		m.result.AlbumsCreated++
+		if err := m.save(); err != nil {
+			return err
+		}
+	}
+
+	// Media items are copied while the page holding them is fresh, since base URLs expire.
+	var order []string
+	batch := &migrationBatch{}
+	err := searchAllMediaItems(m.source, MediaItemsSearchRequest{AlbumID: album.ID}, func(item MediaItem) error {
+		order = append(order, item.ID)
+		return m.copy(batch, albumID, item)
+	})
+	if err == nil {
+		err = m.flush(batch, albumID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Media items copied earlier for another album are added to this one.
+	var current []string
+	err = searchAllMediaItems(m.destination, MediaItemsSearchRequest{AlbumID: albumID}, func(item MediaItem) error {
+		current = append(current, item.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var wanted []string
+	for _, id := range order {
+		if mapped, ok := m.mapping.MediaItems[id]; ok {
+			wanted = append(wanted, mapped)
+		}
+	}
+	if missing := subtractIDs(wanted, current); len(missing) > 0 {
+		bulk, _ := BulkAddMediaItems(m.destination, albumID, missing, BulkOptions{})
+		if len(bulk.Failed) > 0 {
+			return errors.New("adding " + bulk.Failed[0].MediaItemID + " to " + album.Title + ": " + bulk.Failed[0].Err.Error())
+		}
+	}
+
+	if cover, ok := m.mapping.MediaItems[album.CoverPhotoMediaItemID]; ok {
+		_, err := Albums.Patch(m.destination, Album{ID: albumID, CoverPhotoMediaItemID: cover}, AlbumCoverPhotoMediaItemIDField)
+		if err != nil {
+			return err
+		}
+	}
+
+	if enrichments := m.options.Enrichments[album.ID]; len(enrichments) > 0 && !m.mapping.Enrichments[album.ID] {
+		for _, enrichment := range enrichments {
+			_, err := Albums.AddEnrichment(m.destination, albumID, AlbumsAddEnrichmentRequest{
+				NewEnrichmentItem: enrichment,
+				AlbumPosition:     AlbumPosition{Position: LastInAlbum},
+			})
+			if err != nil {
+				return err
+			}
+			m.result.EnrichmentsAdded++
+		}
+		m.mapping.Enrichments[album.ID] = true
+		return m.save()
+	}
+	return nil
+}
+
+type migrationBatch struct {
+	sourceIDs []string
+	items     []NewMediaItem
+}
+
+// copy downloads and uploads the media item unless it has been copied, and creates the batch when it is full.
+func (m *migration) copy(batch *migrationBatch, albumID string, item MediaItem) error {
+	if _, ok := m.mapping.MediaItems[item.ID]; ok {
+		m.result.MediaItemsSkipped++
+		return nil
+	}
+	for _, id := range batch.sourceIDs {
+		if id == item.ID {
+			return nil
+		}
+	}
+
+	filePath := filepath.Join(m.dir, "media")
+	if err := downloadMediaItem(m.source, item, filePath); err != nil {
+		return err
+	}
+	token, err := UploadingMedia.UploadMedia(m.destination, filePath, item.Filename)
+	os.Remove(filePath)
+	if err != nil {
+		return err
+	}
+	batch.sourceIDs = append(batch.sourceIDs, item.ID)
+	batch.items = append(batch.items, NewMediaItem{
+		Description:     item.Description,
+		SimpleMediaItem: SimpleMediaItem{UploadToken: token},
+	})
+	if len(batch.items) >= maxBatchSize {
+		return m.flush(batch, albumID)
+	}
+	return nil
+}
+
+// flush creates the media items of the batch in the destination and records them in the mapping file.
+func (m *migration) flush(batch *migrationBatch, albumID string) error {
+	if len(batch.items) == 0 {
+		return nil
+	}
+	resp, err := MediaItems.BatchCreate(m.destination, MediaItemsBatchCreateRequest{
+		AlbumID:       albumID,
+		NewMediaItems: batch.items,
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.NewMediaItemResults) != len(batch.items) {
+		return errors.New("MediaItems.BatchCreate returned " + strconv.Itoa(len(resp.NewMediaItemResults)) + " results for " + strconv.Itoa(len(batch.items)) + " media items")
+	}
+	// Every created media item is recorded before a failure is reported, so that it isn't copied again.
+	var failure error
+	for i, result := range resp.NewMediaItemResults {
+		if result.Status.Message != "OK" {
+			if failure == nil {
+				failure = errors.New("NewMediaItemResult.Status.Message is not \"OK\": " + result.Status.Message)
+			}
+			continue
+		}
+		m.mapping.MediaItems[batch.sourceIDs[i]] = result.MediaItem.ID
+		m.result.MediaItemsCopied++
+	}
+	*batch = migrationBatch{}
+	if err := m.save(); err != nil {
+		return err
+	}
+	return failure
+}
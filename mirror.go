@@ -0,0 +1,210 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Mirror is the only instance of MirrorMethods(https://godoc.org/github.com/Q-Brains/gphotos#MirrorMethods).
+var Mirror MirrorMethods = mirrorMethods{}
+
+// MirrorMethods is a collection of methods that mirror albums to the local filesystem.
+// The only instance of MirrorMethods is Mirror(https://godoc.org/github.com/Q-Brains/gphotos#Mirror).
+type MirrorMethods interface {
+	// Sync is a method that mirrors every album of Albums.List and SharedAlbums.List into dir.
+	// Each media item is downloaded once into a content store in dir and linked into one directory per album.
+	// Links that were created by a previous Sync but whose media items have left the album are removed.
+	Sync(client *http.Client, dir string, options MirrorOptions) (MirrorResult, error)
+}
+
+// MirrorOptions is options of Mirror.Sync method.
+type MirrorOptions struct {
+	// Symlink creates symbolic links instead of hard links.
+	Symlink bool
+
+	// ExcludeSharedAlbums excludes the albums listed only by SharedAlbums.List.
+	ExcludeSharedAlbums bool
+}
+
+// MirrorResult is the result of Mirror.Sync method.
+type MirrorResult struct {
+	Downloaded []string `json:"downloaded,omitempty"`
+	Linked     []string `json:"linked,omitempty"`
+	Unlinked   []string `json:"unlinked,omitempty"`
+}
+
+const (
+	mirrorStoreDir  = ".store"
+	mirrorStateFile = ".mirror.json"
+)
+
+// mirrorState records the links created in each album directory, so that later runs only remove their own links.
+type mirrorState struct {
+	Links map[string]map[string]string `json:"links"` // album directory -> link name -> media item ID
+}
+
+type mirrorMethods struct{}
+
+func (mirror mirrorMethods) Sync(client *http.Client, dir string, options MirrorOptions) (MirrorResult, error) {
+	var result MirrorResult
+	store := filepath.Join(dir, mirrorStoreDir)
+	if err := os.MkdirAll(store, 0755); err != nil {
+		return result, err
+	}
+
+	state := mirrorState{Links: map[string]map[string]string{}}
+	statePath := filepath.Join(dir, mirrorStateFile)
+	if b, err := ioutil.ReadFile(statePath); err == nil {
+		if err := json.Unmarshal(b, &state); err != nil {
+			return result, err
+		}
+	} else if !os.IsNotExist(err) {
+		return result, err
+	}
+
+	newLinks := map[string]map[string]string{}
+	usedDirs := map[string]bool{}
+	err := walkAlbums(client, !options.ExcludeSharedAlbums, func(album Album, shared bool) (func(MediaItem) error, error) {
+		albumDir := mirrorName(album.Title)
+		if albumDir == "" || usedDirs[albumDir] || albumDir == mirrorStoreDir || albumDir == mirrorStateFile {
+			albumDir = strings.TrimSpace(albumDir + " " + album.ID)
+		}
+		usedDirs[albumDir] = true
+		if err := os.MkdirAll(filepath.Join(dir, albumDir), 0755); err != nil {
+			return nil, err
+		}
+
+		links := map[string]string{}
+		newLinks[albumDir] = links
+		return func(item MediaItem) error {
+			stored := filepath.Join(store, mirrorName(item.ID)+strings.ToLower(path.Ext(item.Filename)))
+			if _, err := os.Stat(stored); os.IsNotExist(err) {
+				if err := downloadMediaItem(client, item, stored); err != nil {
+					return err
+				}
+				result.Downloaded = append(result.Downloaded, stored)
+			} else if err != nil {
+				return err
+			}
+
+			name := mirrorName(item.Filename)
+			if _, ok := links[name]; ok || name == "" {
+				name = mirrorName(item.ID) + "_" + name
+			}
+			links[name] = item.ID
+
+			link := filepath.Join(dir, albumDir, name)
+			if state.Links[albumDir][name] == item.ID {
+				if _, err := os.Lstat(link); err == nil {
+					return nil
+				}
+			}
+			if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if options.Symlink {
+				target, err := filepath.Rel(filepath.Join(dir, albumDir), stored)
+				if err != nil {
+					return err
+				}
+				if err := os.Symlink(target, link); err != nil {
+					return err
+				}
+			} else if err := os.Link(stored, link); err != nil {
+				return err
+			}
+			result.Linked = append(result.Linked, link)
+			return nil
+		}, nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Remove links for media items that left their albums, and directories of albums that disappeared.
+	albumDirs := make([]string, 0, len(state.Links))
+	for albumDir := range state.Links {
+		albumDirs = append(albumDirs, albumDir)
+	}
+	sort.Strings(albumDirs)
+	for _, albumDir := range albumDirs {
+		for name := range state.Links[albumDir] {
+			if _, ok := newLinks[albumDir][name]; ok {
+				continue
+			}
+			link := filepath.Join(dir, albumDir, name)
+			if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+				return result, err
+			}
+			result.Unlinked = append(result.Unlinked, link)
+		}
+		if _, ok := newLinks[albumDir]; !ok {
+			// Only succeeds if the directory is empty.
+			os.Remove(filepath.Join(dir, albumDir))
+		}
+	}
+
+	state.Links = newLinks
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return result, err
+	}
+	return result, ioutil.WriteFile(statePath, b, 0644)
+}
+
+// mirrorName makes s usable as a file name.
+func mirrorName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', 0:
+			return '_'
+		}
+		return r
+	}, s)
+	s = strings.TrimSpace(s)
+	if s == "." || s == ".." {
+		return "_"
+	}
+	return s
+}
+
+// downloadMediaItem downloads the original bytes of the media item to filePath.
+// Source: https://developers.google.com/photos/library/guides/access-media-items#base-urls
+func downloadMediaItem(client *http.Client, item MediaItem, filePath string) error {
+	suffix := "=d"
+	if strings.HasPrefix(item.MimeType, "video/") {
+		suffix = "=dv"
+	}
+	resp, err := client.Get(item.BaseURL + suffix)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.New("failed to download " + item.ID + ": " + resp.Status)
+	}
+
+	tmp := filePath + ".part"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filePath)
+}
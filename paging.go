@@ -0,0 +1,150 @@
+package gphotos
+
+import (
+	"net/http"
+)
+
+// Maximum page sizes accepted by the list and search methods, and maximum number of items accepted by the batch methods.
+const (
+	maxAlbumsPageSize     = 50
+	maxMediaItemsPageSize = 100
+	maxBatchSize          = 50
+)
+
+// searchAllMediaItems pages through MediaItems.Search and calls fn for each media item.
+func searchAllMediaItems(client *http.Client, request MediaItemsSearchRequest, fn func(MediaItem) error) error {
+	if request.PageSize == 0 {
+		request.PageSize = maxMediaItemsPageSize
+	}
+	for {
+		resp, err := MediaItems.Search(client, request)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.MediaItems {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		request.PageToken = resp.NextPageToken
+	}
+}
+
+// listAllMediaItems pages through MediaItems.List and calls fn for each media item.
+func listAllMediaItems(client *http.Client, fn func(MediaItem) error, queries ...ListQuery) error {
+	var nextPageToken string
+	for {
+		q := append([]ListQuery{PageSize(maxMediaItemsPageSize)}, queries...)
+		if nextPageToken != "" {
+			q = append(q, PageToken(nextPageToken))
+		}
+		resp, err := MediaItems.List(client, q...)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.MediaItems {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		nextPageToken = resp.NextPageToken
+		if nextPageToken == "" {
+			return nil
+		}
+	}
+}
+
+// appCreatedMediaItemIDs returns the IDs of the media items created by the app, including archived ones.
+// MediaItems.List has no such filter, so they are searched with Filters.ExcludeNonAppCreatedData.
+func appCreatedMediaItemIDs(client *http.Client) (map[string]bool, error) {
+	ids := map[string]bool{}
+	request := MediaItemsSearchRequest{Filters: Filters{IncludeArchivedMedia: true, ExcludeNonAppCreatedData: true}}
+	err := searchAllMediaItems(client, request, func(item MediaItem) error {
+		ids[item.ID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// walkAlbums lists the albums and, if includeShared, the shared albums the user has joined, visiting each album once.
+// fn is called for each album with whether it was listed by SharedAlbums.List.
+// If fn returns a media item callback, it is called for each media item of the album in album order, while its page is fresh.
+// Media items can be swapped without changing the item count or cover of an album,
+// so callers that skip albums whose count and cover are unchanged can miss such changes.
+func walkAlbums(client *http.Client, includeShared bool, fn func(album Album, shared bool) (func(MediaItem) error, error)) error {
+	seen := map[string]bool{}
+	visit := func(shared bool) func(Album) error {
+		return func(album Album) error {
+			if seen[album.ID] {
+				return nil
+			}
+			seen[album.ID] = true
+			itemFn, err := fn(album, shared)
+			if err != nil || itemFn == nil {
+				return err
+			}
+			return searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: album.ID}, itemFn)
+		}
+	}
+	if err := listAllAlbums(client, visit(false)); err != nil {
+		return err
+	}
+	if !includeShared {
+		return nil
+	}
+	return listAllSharedAlbums(client, visit(true))
+}
+
+// listAllAlbums pages through Albums.List and calls fn for each album.
+func listAllAlbums(client *http.Client, fn func(Album) error, queries ...ListQuery) error {
+	var nextPageToken string
+	for {
+		q := append([]ListQuery{PageSize(maxAlbumsPageSize)}, queries...)
+		if nextPageToken != "" {
+			q = append(q, PageToken(nextPageToken))
+		}
+		resp, err := Albums.List(client, q...)
+		if err != nil {
+			return err
+		}
+		for _, album := range resp.Albums {
+			if err := fn(album); err != nil {
+				return err
+			}
+		}
+		nextPageToken = resp.NextPageToken
+		if nextPageToken == "" {
+			return nil
+		}
+	}
+}
+
+// listAllSharedAlbums pages through SharedAlbums.List and calls fn for each shared album.
+func listAllSharedAlbums(client *http.Client, fn func(Album) error, queries ...ListQuery) error {
+	var nextPageToken string
+	for {
+		q := append([]ListQuery{PageSize(maxAlbumsPageSize)}, queries...)
+		if nextPageToken != "" {
+			q = append(q, PageToken(nextPageToken))
+		}
+		resp, err := SharedAlbums.List(client, q...)
+		if err != nil {
+			return err
+		}
+		for _, album := range resp.SharedAlbums {
+			if err := fn(album); err != nil {
+				return err
+			}
+		}
+		nextPageToken = resp.NextPageToken
+		if nextPageToken == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package gphotos
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// MediaItemFilter is a predicate on MediaItems used with FilteredSearch and FilteredList.
+// Filters that the API supports are also pushed down into the Filters of MediaItems.Search,
+// and the rest is checked on the client.
+// For example, photos taken in 2022 with a Pixel 7 at least 4000 pixels wide are matched by
+//
+//	AllOf(OfMediaType(PhotoType), CreatedInYear(2022), CameraModel("Pixel 7"), MinWidth(4000))
+type MediaItemFilter struct {
+	match    func(MediaItem) bool
+	pushDown func(*Filters)
+
+	// apiOnly is set when the filter can't be checked on the client, such as content categories.
+	apiOnly bool
+	err     error
+
+	// categoryFilters counts the InCategories filters, since the API ORs included content categories.
+	categoryFilters int
+}
+
+// Match reports whether the media item satisfies the parts of the filter that can be checked on the client.
+func (filter MediaItemFilter) Match(item MediaItem) bool {
+	return filter.match == nil || filter.match(item)
+}
+
+// Filters returns the part of the filter that is pushed down into MediaItems.Search.
+// It matches a superset of the media items matched by the filter.
+func (filter MediaItemFilter) Filters() Filters {
+	var filters Filters
+	if filter.pushDown != nil {
+		filter.pushDown(&filters)
+	}
+	return filters
+}
+
+// MatchFunc returns a MediaItemFilter checking fn on the client.
+func MatchFunc(fn func(MediaItem) bool) MediaItemFilter {
+	return MediaItemFilter{match: fn}
+}
+
+// AllOf returns a MediaItemFilter matching media items that match every filter.
+// The API matches any of the included content categories, so at most one InCategories filter can be used.
+func AllOf(filters ...MediaItemFilter) MediaItemFilter {
+	result := MediaItemFilter{
+		match: func(item MediaItem) bool {
+			for _, filter := range filters {
+				if !filter.Match(item) {
+					return false
+				}
+			}
+			return true
+		},
+		pushDown: func(f *Filters) {
+			for _, filter := range filters {
+				if filter.pushDown != nil {
+					filter.pushDown(f)
+				}
+			}
+		},
+	}
+	for _, filter := range filters {
+		result.apiOnly = result.apiOnly || filter.apiOnly
+		result.categoryFilters += filter.categoryFilters
+		if result.err == nil {
+			result.err = filter.err
+		}
+	}
+	if result.categoryFilters > 1 && result.err == nil {
+		result.err = errors.New("the API matches any of the included content categories, so only one InCategories can be used in AllOf")
+	}
+	return result
+}
+
+// AnyOf returns a MediaItemFilter matching media items that match at least one filter.
+// It is checked on the client only, so filters that can only be checked by the API can't be used.
+func AnyOf(filters ...MediaItemFilter) MediaItemFilter {
+	result := MediaItemFilter{
+		match: func(item MediaItem) bool {
+			for _, filter := range filters {
+				if filter.Match(item) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	for _, filter := range filters {
+		if filter.err != nil {
+			result.err = filter.err
+		} else if filter.apiOnly {
+			result.err = errors.New("filters checked only by the API can't be used in AnyOf")
+		}
+	}
+	return result
+}
+
+// Not returns a MediaItemFilter matching media items that don't match filter.
+// It is checked on the client only, so filters that can only be checked by the API can't be used.
+func Not(filter MediaItemFilter) MediaItemFilter {
+	result := MediaItemFilter{
+		match: func(item MediaItem) bool {
+			return !filter.Match(item)
+		},
+		err: filter.err,
+	}
+	if filter.apiOnly && result.err == nil {
+		result.err = errors.New("filters checked only by the API can't be used in Not")
+	}
+	return result
+}
+
+// OfMediaType returns a MediaItemFilter matching photos or videos.
+func OfMediaType(mediaType MediaType) MediaItemFilter {
+	return MediaItemFilter{
+		match: func(item MediaItem) bool {
+			switch mediaType {
+			case PhotoType:
+				return strings.HasPrefix(item.MimeType, "image/")
+			case VideoType:
+				return strings.HasPrefix(item.MimeType, "video/")
+			}
+			return true
+		},
+		pushDown: func(f *Filters) {
+			if mediaType != AllMedia {
+				f.MediaTypeFilter.MediaTypes = append(f.MediaTypeFilter.MediaTypes, mediaType)
+			}
+		},
+	}
+}
+
+// CreatedBetween returns a MediaItemFilter matching media items created at or after start and before end.
+func CreatedBetween(start time.Time, end time.Time) MediaItemFilter {
+	return MediaItemFilter{
+		match: func(item MediaItem) bool {
+			t := item.MediaMetadata.CreationTime
+			return !t.Before(start) && t.Before(end)
+		},
+		pushDown: func(f *Filters) {
+			// The API compares dates in the local time of the media, so widen the range by a day on both sides.
+			f.DateFilter.Ranges = append(f.DateFilter.Ranges, DateRange{
+				StartDate: searchDateOf(start.UTC().AddDate(0, 0, -1)),
+				EndDate:   searchDateOf(end.UTC().AddDate(0, 0, 1)),
+			})
+		},
+	}
+}
+
+// searchDateOf returns the Date of t clamped to the years 1 to 9999.
+// An unbounded side such as the zero time.Time would otherwise give year 0, which the API reads as any year.
+func searchDateOf(t time.Time) Date {
+	switch {
+	case t.Year() < 1:
+		return Date{Year: 1, Month: 1, Day: 1}
+	case t.Year() > 9999:
+		return Date{Year: 9999, Month: 12, Day: 31}
+	}
+	return DateOf(t)
+}
+
+// CreatedInYear returns a MediaItemFilter matching media items created in the year in UTC.
+func CreatedInYear(year int) MediaItemFilter {
+	return CreatedBetween(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+// TimeOfDayBetween returns a MediaItemFilter matching media items created between start and end after midnight in loc.
+// If start is later than end, the range wraps around midnight.
+func TimeOfDayBetween(start time.Duration, end time.Duration, loc *time.Location) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		t := item.MediaMetadata.CreationTime.In(loc)
+		d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+		if start <= end {
+			return d >= start && d < end
+		}
+		return d >= start || d < end
+	})
+}
+
+// CameraMake returns a MediaItemFilter matching media items taken with a camera of the make, ignoring case.
+func CameraMake(cameraMake string) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		return strings.EqualFold(item.MediaMetadata.CameraMake(), cameraMake)
+	})
+}
+
+// CameraModel returns a MediaItemFilter matching media items taken with a camera of the model, ignoring case.
+func CameraModel(model string) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		return strings.EqualFold(item.MediaMetadata.CameraModel(), model)
+	})
+}
+
+// FilenameMatches returns a MediaItemFilter matching media items whose filename matches the pattern of path.Match.
+func FilenameMatches(pattern string) MediaItemFilter {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return MediaItemFilter{err: err}
+	}
+	return MatchFunc(func(item MediaItem) bool {
+		matched, _ := path.Match(pattern, item.Filename)
+		return matched
+	})
+}
+
+// MimeType returns a MediaItemFilter matching media items of one of the MIME types.
+// A type ending with "/*", such as "image/*", matches every subtype.
+func MimeType(mimeTypes ...string) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		for _, mimeType := range mimeTypes {
+			if strings.HasSuffix(mimeType, "/*") && strings.HasPrefix(item.MimeType, strings.TrimSuffix(mimeType, "*")) {
+				return true
+			}
+			if strings.EqualFold(item.MimeType, mimeType) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// MinWidth returns a MediaItemFilter matching media items at least width pixels wide.
+func MinWidth(width int64) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		return item.MediaMetadata.Width >= width
+	})
+}
+
+// MinHeight returns a MediaItemFilter matching media items at least height pixels high.
+func MinHeight(height int64) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		return item.MediaMetadata.Height >= height
+	})
+}
+
+// AspectRatioBetween returns a MediaItemFilter matching media items whose width divided by height is from min to max.
+func AspectRatioBetween(min float64, max float64) MediaItemFilter {
+	return MatchFunc(func(item MediaItem) bool {
+		ratio := item.MediaMetadata.AspectRatio()
+		return ratio != 0 && ratio >= min && ratio <= max
+	})
+}
+
+// InCategories returns a MediaItemFilter matching media items in at least one of the content categories.
+// It can only be checked by the API, which can't require several categories at once,
+// so AllOf rejects more than one InCategories.
+func InCategories(categories ...ContentCategory) MediaItemFilter {
+	return MediaItemFilter{
+		pushDown: func(f *Filters) {
+			f.ContentFilter.IncludedContentCategories = appendCategories(f.ContentFilter.IncludedContentCategories, categories)
+		},
+		apiOnly:         true,
+		categoryFilters: 1,
+	}
+}
+
+// NotInCategories returns a MediaItemFilter matching media items in none of the content categories.
+// It can only be checked by the API.
+func NotInCategories(categories ...ContentCategory) MediaItemFilter {
+	return MediaItemFilter{
+		pushDown: func(f *Filters) {
+			f.ContentFilter.ExcludedContentCategories = appendCategories(f.ContentFilter.ExcludedContentCategories, categories)
+		},
+		apiOnly: true,
+	}
+}
+
+// IsFavorite returns a MediaItemFilter matching favorites. It can only be checked by the API.
+func IsFavorite() MediaItemFilter {
+	return MediaItemFilter{
+		pushDown: func(f *Filters) {
+			f.FeatureFilter.IncludedFeatures = []Feature{Favorites}
+		},
+		apiOnly: true,
+	}
+}
+
+// FilteredSearch calls fn for each media item returned by MediaItems.Search that matches filter.
+// The filter is pushed down into request.Filters when request has no AlbumID.
+// If the pushed down filters violate the limits of the API, the filter is checked on the client only.
+func FilteredSearch(client *http.Client, request MediaItemsSearchRequest, filter MediaItemFilter, fn func(MediaItem) error) error {
+	if filter.err != nil {
+		return filter.err
+	}
+	if request.AlbumID != "" {
+		if filter.apiOnly {
+			return errors.New("filters checked only by the API can't be combined with AlbumID")
+		}
+	} else if filter.pushDown != nil {
+		pushed := request
+		filter.pushDown(&pushed.Filters)
+		if err := ValidateSearchRequest(pushed); err == nil {
+			request = pushed
+		} else if filter.apiOnly {
+			return err
+		}
+	}
+	return searchAllMediaItems(client, request, func(item MediaItem) error {
+		if !filter.Match(item) {
+			return nil
+		}
+		return fn(item)
+	})
+}
+
+// FilteredList calls fn for each media item returned by MediaItems.List that matches filter.
+// Filters that can only be checked by the API can't be used.
+func FilteredList(client *http.Client, filter MediaItemFilter, fn func(MediaItem) error, queries ...ListQuery) error {
+	if filter.err != nil {
+		return filter.err
+	}
+	if filter.apiOnly {
+		return errors.New("filters checked only by the API can't be used with MediaItems.List")
+	}
+	return listAllMediaItems(client, func(item MediaItem) error {
+		if !filter.Match(item) {
+			return nil
+		}
+		return fn(item)
+	}, queries...)
+}
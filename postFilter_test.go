@@ -0,0 +1,59 @@
+package gphotos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreatedBetweenOpenEnded(t *testing.T) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+	for _, c := range []struct {
+		start, end time.Time
+		want       DateRange
+		match      []time.Time
+		noMatch    []time.Time
+	}{
+		{
+			time.Time{}, at(2020, time.March, 1),
+			DateRange{StartDate: Date{Year: 1, Month: 1, Day: 1}, EndDate: Date{Year: 2020, Month: 3, Day: 2}},
+			[]time.Time{at(1990, time.June, 1), at(2020, time.February, 29)},
+			[]time.Time{at(2020, time.March, 1), at(2021, time.January, 1)},
+		},
+		{
+			at(2020, time.March, 1), time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			DateRange{StartDate: Date{Year: 2020, Month: 2, Day: 29}, EndDate: Date{Year: 9999, Month: 12, Day: 31}},
+			[]time.Time{at(2020, time.March, 1), at(2300, time.January, 1)},
+			[]time.Time{at(2020, time.February, 29)},
+		},
+		{
+			time.Time{}, time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			DateRange{StartDate: Date{Year: 1, Month: 1, Day: 1}, EndDate: Date{Year: 9999, Month: 12, Day: 31}},
+			[]time.Time{at(1, time.January, 1), at(2020, time.March, 1)},
+			nil,
+		},
+	} {
+		filter := CreatedBetween(c.start, c.end)
+		var f Filters
+		filter.pushDown(&f)
+		if len(f.DateFilter.Ranges) != 1 || f.DateFilter.Ranges[0] != c.want {
+			t.Errorf("CreatedBetween(%v, %v) pushed down %+v, want %+v", c.start, c.end, f.DateFilter.Ranges, c.want)
+		}
+		for _, r := range f.DateFilter.Ranges {
+			if p := r.StartDate.problem() + r.EndDate.problem(); p != "" {
+				t.Errorf("CreatedBetween(%v, %v) pushed down an invalid range: %s", c.start, c.end, p)
+			}
+		}
+		for _, created := range c.match {
+			if !filter.Match(MediaItem{MediaMetadata: MediaMetadata{CreationTime: created}}) {
+				t.Errorf("CreatedBetween(%v, %v) doesn't match %v", c.start, c.end, created)
+			}
+		}
+		for _, created := range c.noMatch {
+			if filter.Match(MediaItem{MediaMetadata: MediaMetadata{CreationTime: created}}) {
+				t.Errorf("CreatedBetween(%v, %v) matches %v", c.start, c.end, created)
+			}
+		}
+	}
+}
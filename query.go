@@ -0,0 +1,516 @@
+package gphotos
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a parsed text query. Use ParseQuery to create a Query.
+//
+// A query is a list of terms separated by spaces. Values containing spaces are quoted with `"`.
+// In quotes, `\"` and `\\` stand for `"` and `\`.
+//
+//	type:video|photo|all        media type
+//	after:DATE                  media created on or after DATE
+//	before:DATE                 media created on or before DATE
+//	on:DATE                     media created on DATE
+//	range:DATE..DATE            media created in an inclusive range, can be repeated
+//	category:NAME,...           included content categories, such as pets or travel
+//	-category:NAME,...          excluded content categories
+//	favorite                    favorites only
+//	archived                    include archived media
+//	appcreated                  media created by this app only
+//	album:"TITLE"               media in the album with the title
+//	albumid:ID                  media in the album with the ID
+//
+// DATE is YYYY, YYYY-MM or YYYY-MM-DD. `on:` also accepts *-MM-DD and *-MM for every year.
+//
+// The API rejects an album combined with filters, so such queries can't be sent as a single MediaItemsSearchRequest.
+// Query.Search handles them by intersecting the filtered search with the media items of the album.
+type Query struct {
+	// Filters are the filters given by the query.
+	Filters Filters
+
+	// AlbumID is the album given by `albumid:`, or resolved from AlbumTitle by Query.Resolve.
+	AlbumID string
+
+	// AlbumTitle is the title given by `album:`.
+	AlbumTitle string
+
+	albumOffset int
+}
+
+// QueryError is the error returned for an invalid query. Offset is the byte offset of the invalid part in the query.
+type QueryError struct {
+	Offset  int
+	Message string
+}
+
+func (err *QueryError) Error() string {
+	return fmt.Sprintf("query: offset %d: %s", err.Offset, err.Message)
+}
+
+type queryTerm struct {
+	key         string
+	value       string
+	offset      int
+	valueOffset int
+	negated     bool
+}
+
+// ParseQuery parses a text query. Album titles are resolved by Query.Resolve.
+func ParseQuery(query string) (Query, error) {
+	terms, err := splitQuery(query)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var q Query
+	filters := &q.Filters
+	var after, before *Date
+	var afterOffset, beforeOffset, rangeOffset int
+	// categoryOffsets are the offsets of the included and excluded categories, to report conflicts where they occur.
+	categoryOffsets := map[bool]map[ContentCategory]int{false: {}, true: {}}
+	seen := map[string]int{}
+	for _, term := range terms {
+		key := term.key
+		if term.negated {
+			key = "-" + key
+		}
+		switch key {
+		case "type", "after", "before", "album", "albumid":
+			if offset, ok := seen[key]; ok {
+				return Query{}, &QueryError{Offset: term.offset, Message: fmt.Sprintf("%q is already given at offset %d", key+":", offset)}
+			}
+			seen[key] = term.offset
+		}
+
+		switch key {
+		case "type":
+			var t MediaType
+			switch strings.ToLower(term.value) {
+			case "all":
+				t = AllMedia
+			case "video":
+				t = VideoType
+			case "photo":
+				t = PhotoType
+			default:
+				return Query{}, &QueryError{Offset: term.valueOffset, Message: fmt.Sprintf("unknown media type %q, want video, photo or all", term.value)}
+			}
+			filters.MediaTypeFilter.MediaTypes = []MediaType{t}
+		case "after", "before":
+			date, err := parseQueryDate(term.value, term.valueOffset, false)
+			if err != nil {
+				return Query{}, err
+			}
+			if key == "after" {
+				after, afterOffset = &date, term.offset
+			} else {
+				before, beforeOffset = &date, term.offset
+			}
+		case "on":
+			date, err := parseQueryDate(term.value, term.valueOffset, true)
+			if err != nil {
+				return Query{}, err
+			}
+			filters.DateFilter.Dates = append(filters.DateFilter.Dates, date)
+			if len(filters.DateFilter.Dates) > maxSearchDates {
+				return Query{}, &QueryError{Offset: term.offset, Message: fmt.Sprintf("up to %d dates can be specified", maxSearchDates)}
+			}
+		case "range":
+			bounds := strings.SplitN(term.value, "..", 2)
+			if len(bounds) != 2 {
+				return Query{}, &QueryError{Offset: term.valueOffset, Message: "range must be DATE..DATE"}
+			}
+			start, err := parseQueryDate(bounds[0], term.valueOffset, false)
+			if err != nil {
+				return Query{}, err
+			}
+			end, err := parseQueryDate(bounds[1], term.valueOffset+len(bounds[0])+2, false)
+			if err != nil {
+				return Query{}, err
+			}
+			filters.DateFilter.Ranges = append(filters.DateFilter.Ranges, DateRange{StartDate: firstDay(start), EndDate: lastDay(end)})
+			rangeOffset = term.offset
+			if len(filters.DateFilter.Ranges) > maxSearchRanges {
+				return Query{}, &QueryError{Offset: term.offset, Message: fmt.Sprintf("up to %d date ranges can be specified", maxSearchRanges)}
+			}
+		case "category", "-category":
+			offset := term.valueOffset
+			for _, name := range strings.Split(term.value, ",") {
+				category, ok := parseContentCategory(name)
+				if !ok {
+					return Query{}, &QueryError{Offset: offset, Message: fmt.Sprintf("unknown category %q", name)}
+				}
+				if _, ok := categoryOffsets[!term.negated][category]; ok {
+					return Query{}, &QueryError{Offset: offset, Message: fmt.Sprintf("category %q is both included and excluded", name)}
+				}
+				if _, ok := categoryOffsets[term.negated][category]; !ok {
+					categoryOffsets[term.negated][category] = offset
+				}
+				if term.negated {
+					filters.ContentFilter.ExcludedContentCategories = appendCategories(filters.ContentFilter.ExcludedContentCategories, []ContentCategory{category})
+				} else {
+					filters.ContentFilter.IncludedContentCategories = appendCategories(filters.ContentFilter.IncludedContentCategories, []ContentCategory{category})
+					if len(filters.ContentFilter.IncludedContentCategories) > maxSearchIncludedCategories {
+						return Query{}, &QueryError{Offset: offset, Message: fmt.Sprintf("up to %d content categories can be included", maxSearchIncludedCategories)}
+					}
+				}
+				offset += len(name) + 1
+			}
+		case "favorite", "favorites":
+			if term.value != "" {
+				return Query{}, &QueryError{Offset: term.valueOffset, Message: fmt.Sprintf("%q takes no value", term.key)}
+			}
+			filters.FeatureFilter.IncludedFeatures = []Feature{Favorites}
+		case "archived":
+			if term.value != "" {
+				return Query{}, &QueryError{Offset: term.valueOffset, Message: "\"archived\" takes no value"}
+			}
+			filters.IncludeArchivedMedia = true
+		case "appcreated":
+			if term.value != "" {
+				return Query{}, &QueryError{Offset: term.valueOffset, Message: "\"appcreated\" takes no value"}
+			}
+			filters.ExcludeNonAppCreatedData = true
+		case "album":
+			if _, ok := seen["albumid"]; ok {
+				return Query{}, &QueryError{Offset: term.offset, Message: "\"album:\" can't be combined with \"albumid:\""}
+			}
+			q.AlbumTitle, q.albumOffset = term.value, term.offset
+		case "albumid":
+			if _, ok := seen["album"]; ok {
+				return Query{}, &QueryError{Offset: term.offset, Message: "\"albumid:\" can't be combined with \"album:\""}
+			}
+			q.AlbumID = term.value
+		default:
+			return Query{}, &QueryError{Offset: term.offset, Message: fmt.Sprintf("unknown term %q", key)}
+		}
+	}
+
+	if after != nil || before != nil {
+		r := DateRange{StartDate: Date{Year: 1, Month: 1, Day: 1}, EndDate: Date{Year: 9999, Month: 12, Day: 31}}
+		if after != nil {
+			r.StartDate = firstDay(*after)
+		}
+		if before != nil {
+			r.EndDate = lastDay(*before)
+		}
+		if r.EndDate.before(r.StartDate) {
+			return Query{}, &QueryError{Offset: afterOffset, Message: fmt.Sprintf("after:%s is later than before:%s", r.StartDate, r.EndDate)}
+		}
+		filters.DateFilter.Ranges = append([]DateRange{r}, filters.DateFilter.Ranges...)
+		if len(filters.DateFilter.Ranges) > maxSearchRanges {
+			// after: and before: take one date range, counted where the first of them is given.
+			offset := afterOffset
+			if after == nil || (before != nil && beforeOffset < afterOffset) {
+				offset = beforeOffset
+			}
+			if rangeOffset > offset {
+				offset = rangeOffset
+			}
+			return Query{}, &QueryError{Offset: offset, Message: fmt.Sprintf("up to %d date ranges can be specified, including after: and before:", maxSearchRanges)}
+		}
+	}
+
+	// The limits are checked above where a term exceeds them, so this only catches problems without a term.
+	if err := ValidateSearchRequest(MediaItemsSearchRequest{Filters: q.Filters}); err != nil {
+		return Query{}, &QueryError{Offset: 0, Message: err.Error()}
+	}
+	return q, nil
+}
+
+// Resolve is a method that resolves the album title of the Query to an ID with Albums.List.
+func (query Query) Resolve(client *http.Client) (Query, error) {
+	if query.AlbumTitle == "" || query.AlbumID != "" {
+		return query, nil
+	}
+	var ids []string
+	err := listAllAlbums(client, func(album Album) error {
+		if album.Title == query.AlbumTitle {
+			ids = append(ids, album.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return Query{}, err
+	}
+	switch len(ids) {
+	case 0:
+		return Query{}, &QueryError{Offset: query.albumOffset, Message: fmt.Sprintf("no album titled %q", query.AlbumTitle)}
+	case 1:
+		query.AlbumID = ids[0]
+		return query, nil
+	default:
+		return Query{}, &QueryError{Offset: query.albumOffset, Message: fmt.Sprintf("%d albums are titled %q, use albumid: instead", len(ids), query.AlbumTitle)}
+	}
+}
+
+// Request returns the MediaItemsSearchRequest of the Query.
+// It returns an error if the Query has both an album and filters, or if its album title hasn't been resolved.
+func (query Query) Request() (MediaItemsSearchRequest, error) {
+	if query.AlbumID == "" && query.AlbumTitle != "" {
+		return MediaItemsSearchRequest{}, &QueryError{Offset: query.albumOffset, Message: "album title isn't resolved"}
+	}
+	request := MediaItemsSearchRequest{AlbumID: query.AlbumID, Filters: query.Filters}
+	if err := ValidateSearchRequest(request); err != nil {
+		return MediaItemsSearchRequest{}, err
+	}
+	return request, nil
+}
+
+// Search is a method that calls fn for each media item matching the Query.
+// An album combined with filters is searched with the filters, keeping the media items of the album.
+func (query Query) Search(client *http.Client, fn func(MediaItem) error) error {
+	query, err := query.Resolve(client)
+	if err != nil {
+		return err
+	}
+	if query.AlbumID == "" || query.Filters.isEmpty() {
+		request, err := query.Request()
+		if err != nil {
+			return err
+		}
+		return searchAllMediaItems(client, request, fn)
+	}
+
+	inAlbum := map[string]bool{}
+	err = searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: query.AlbumID}, func(item MediaItem) error {
+		inAlbum[item.ID] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return searchAllMediaItems(client, MediaItemsSearchRequest{Filters: query.Filters}, func(item MediaItem) error {
+		if !inAlbum[item.ID] {
+			return nil
+		}
+		return fn(item)
+	})
+}
+
+// String returns the text query of the Query.
+func (query Query) String() string {
+	var terms []string
+	if query.AlbumTitle != "" {
+		terms = append(terms, "album:"+quoteQueryString(query.AlbumTitle))
+	} else if query.AlbumID != "" {
+		terms = append(terms, "albumid:"+quoteQueryValue(query.AlbumID))
+	}
+	if s := FormatQuery(MediaItemsSearchRequest{Filters: query.Filters}); s != "" {
+		terms = append(terms, s)
+	}
+	return strings.Join(terms, " ")
+}
+
+// CompileQuery parses a text query and resolves its album title with Albums.List.
+func CompileQuery(client *http.Client, query string) (Query, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return Query{}, err
+	}
+	return q.Resolve(client)
+}
+
+// FormatQuery returns a text query equivalent to the request. Albums are given by `albumid:`.
+func FormatQuery(request MediaItemsSearchRequest) string {
+	var terms []string
+	if request.AlbumID != "" {
+		terms = append(terms, "albumid:"+quoteQueryValue(request.AlbumID))
+	}
+	filters := request.Filters
+	for _, t := range filters.MediaTypeFilter.MediaTypes {
+		switch t {
+		case VideoType:
+			terms = append(terms, "type:video")
+		case PhotoType:
+			terms = append(terms, "type:photo")
+		default:
+			terms = append(terms, "type:all")
+		}
+	}
+	for _, date := range filters.DateFilter.Dates {
+		terms = append(terms, "on:"+formatQueryDate(date))
+	}
+	ranges := filters.DateFilter.Ranges
+	if len(ranges) == 1 {
+		if r := ranges[0]; r.StartDate != (Date{Year: 1, Month: 1, Day: 1}) {
+			terms = append(terms, "after:"+formatQueryDate(r.StartDate))
+		}
+		if r := ranges[0]; r.EndDate != (Date{Year: 9999, Month: 12, Day: 31}) {
+			terms = append(terms, "before:"+formatQueryDate(r.EndDate))
+		}
+	} else {
+		for _, r := range ranges {
+			terms = append(terms, "range:"+formatQueryDate(r.StartDate)+".."+formatQueryDate(r.EndDate))
+		}
+	}
+	if names := categoryNames(filters.ContentFilter.IncludedContentCategories); names != "" {
+		terms = append(terms, "category:"+names)
+	}
+	if names := categoryNames(filters.ContentFilter.ExcludedContentCategories); names != "" {
+		terms = append(terms, "-category:"+names)
+	}
+	for _, feature := range filters.FeatureFilter.IncludedFeatures {
+		if feature == Favorites {
+			terms = append(terms, "favorite")
+		}
+	}
+	if filters.IncludeArchivedMedia {
+		terms = append(terms, "archived")
+	}
+	if filters.ExcludeNonAppCreatedData {
+		terms = append(terms, "appcreated")
+	}
+	return strings.Join(terms, " ")
+}
+
+func splitQuery(query string) ([]queryTerm, error) {
+	var terms []queryTerm
+	i := 0
+	for i < len(query) {
+		if query[i] == ' ' || query[i] == '\t' || query[i] == '\n' {
+			i++
+			continue
+		}
+		term := queryTerm{offset: i, valueOffset: -1}
+		var sb strings.Builder
+		for i < len(query) && query[i] != ' ' && query[i] != '\t' && query[i] != '\n' {
+			switch {
+			case query[i] == '"':
+				if term.valueOffset < 0 && term.key == "" && sb.Len() > 0 {
+					return nil, &QueryError{Offset: i, Message: "quote before \":\""}
+				}
+				start := i
+				for i++; i < len(query) && query[i] != '"'; i++ {
+					if query[i] == '\\' && i+1 < len(query) {
+						i++
+					}
+					sb.WriteByte(query[i])
+				}
+				if i >= len(query) {
+					return nil, &QueryError{Offset: start, Message: "unterminated quote"}
+				}
+				i++
+			case query[i] == ':' && term.valueOffset < 0:
+				term.key = sb.String()
+				sb.Reset()
+				term.valueOffset = i + 1
+				i++
+			default:
+				sb.WriteByte(query[i])
+				i++
+			}
+		}
+		if term.valueOffset < 0 {
+			term.key = sb.String()
+			term.valueOffset = i
+		} else {
+			term.value = sb.String()
+			if term.value == "" {
+				return nil, &QueryError{Offset: term.valueOffset, Message: fmt.Sprintf("%q needs a value", term.key+":")}
+			}
+		}
+		if strings.HasPrefix(term.key, "-") {
+			term.key, term.negated = term.key[1:], true
+		}
+		term.key = strings.ToLower(term.key)
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// parseQueryDate parses YYYY, YYYY-MM or YYYY-MM-DD. If anyYear is true, *-MM and *-MM-DD are accepted too.
+func parseQueryDate(value string, offset int, anyYear bool) (Date, error) {
+	invalid := &QueryError{Offset: offset, Message: fmt.Sprintf("invalid date %q, want YYYY, YYYY-MM or YYYY-MM-DD", value)}
+	parts := strings.Split(value, "-")
+	if len(parts) > 3 {
+		return Date{}, invalid
+	}
+	var fields [3]int
+	for i, part := range parts {
+		if i == 0 && part == "*" && anyYear && len(parts) > 1 {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n <= 0 {
+			return Date{}, invalid
+		}
+		fields[i] = n
+	}
+	date := Date{Year: fields[0], Month: fields[1], Day: fields[2]}
+	if problem := date.problem(); problem != "" {
+		return Date{}, &QueryError{Offset: offset, Message: problem}
+	}
+	return date, nil
+}
+
+func firstDay(date Date) Date {
+	if date.Month == 0 {
+		date.Month = 1
+	}
+	if date.Day == 0 {
+		date.Day = 1
+	}
+	return date
+}
+
+func lastDay(date Date) Date {
+	if date.Month == 0 {
+		date.Month = 12
+	}
+	if date.Day == 0 {
+		date.Day = time.Date(date.Year, time.Month(date.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	}
+	return date
+}
+
+func formatQueryDate(date Date) string {
+	year := strconv.Itoa(date.Year)
+	if date.Year == 0 {
+		year = "*"
+	}
+	switch {
+	case date.Month == 0:
+		return year
+	case date.Day == 0:
+		return fmt.Sprintf("%s-%02d", year, date.Month)
+	default:
+		return fmt.Sprintf("%s-%02d-%02d", year, date.Month, date.Day)
+	}
+}
+
+func parseContentCategory(name string) (ContentCategory, bool) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	for i, n := range contentCategoryNames {
+		if n == upper && ContentCategory(i) != ContentCategoryNone {
+			return ContentCategory(i), true
+		}
+	}
+	return ContentCategoryNone, false
+}
+
+func categoryNames(categories []ContentCategory) string {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = strings.ToLower(category.String())
+	}
+	return strings.Join(names, ",")
+}
+
+func quoteQueryValue(value string) string {
+	if strings.ContainsAny(value, " \t\n:\"") {
+		return quoteQueryString(value)
+	}
+	return value
+}
+
+// quoteQueryString quotes the value, escaping `"` and `\` with a backslash.
+func quoteQueryString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
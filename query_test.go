@@ -0,0 +1,56 @@
+package gphotos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryStringRoundTrip(t *testing.T) {
+	for _, query := range []Query{
+		{AlbumTitle: "Paris"},
+		{AlbumTitle: "Summer 2020: beach"},
+		{AlbumTitle: `The "best" of`},
+		{AlbumTitle: `C:\Photos\ `},
+		{AlbumTitle: `"`},
+		{AlbumID: "abc"},
+		{AlbumID: `a"b c`},
+		{AlbumTitle: `Kids "2019"`, Filters: Filters{FeatureFilter: FeatureFilter{IncludedFeatures: []Feature{Favorites}}}},
+	} {
+		s := query.String()
+		parsed, err := ParseQuery(s)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %v", s, err)
+			continue
+		}
+		if parsed.AlbumTitle != query.AlbumTitle || parsed.AlbumID != query.AlbumID || !reflect.DeepEqual(parsed.Filters, query.Filters) {
+			t.Errorf("ParseQuery(%q) = %+v, want %+v", s, parsed, query)
+		}
+	}
+}
+
+func TestParseQueryQuotes(t *testing.T) {
+	for query, title := range map[string]string{
+		`album:"a b"`:      "a b",
+		`album:"a \"b\""`:  `a "b"`,
+		`album:"a\\b"`:     `a\b`,
+		`album:a\b`:        `a\b`,
+		`album:"a "b" c"`:  "a b c",
+		`album:x"y z"`:     "xy z",
+		`album:"\z"`:       "z",
+		`album:"a\"b"tail`: `a"btail`,
+	} {
+		parsed, err := ParseQuery(query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %v", query, err)
+			continue
+		}
+		if parsed.AlbumTitle != title {
+			t.Errorf("ParseQuery(%q).AlbumTitle = %q, want %q", query, parsed.AlbumTitle, title)
+		}
+	}
+	for _, query := range []string{`album:"a`, `album:"a\"`, `album:"a\`} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) returned no error", query)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package gphotos
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Reorganizer is the only instance of ReorganizeMethods(https://godoc.org/github.com/Q-Brains/gphotos#ReorganizeMethods).
+var Reorganizer ReorganizeMethods = reorganizeMethods{}
+
+// ReorganizeMethods is a collection of methods that merge and split albums.
+// Only media items created by the app can be added to albums, and only albums created by the app can be changed,
+// so the other media items are reported in Unmovable and left in place.
+// The API can't delete albums, so emptied source albums remain.
+// The only instance of ReorganizeMethods is Reorganizer(https://godoc.org/github.com/Q-Brains/gphotos#Reorganizer).
+type ReorganizeMethods interface {
+	// Merge is a method that moves the media items of the source albums into a new album titled title.
+	Merge(client *http.Client, sourceAlbumIDs []string, title string, options ReorganizeOptions) (Reorganization, error)
+
+	// Split is a method that moves the media items of an album into new albums per day or month of their creation time.
+	// The new albums are titled with the title of the source album followed by the date.
+	Split(client *http.Client, sourceAlbumID string, period SplitPeriod, options ReorganizeOptions) (Reorganization, error)
+}
+
+// ReorganizeOptions is options of Reorganizer.Merge and Reorganizer.Split methods.
+type ReorganizeOptions struct {
+	// DryRun computes the Reorganization without creating albums or moving media items.
+	DryRun bool
+
+	// KeepSources copies the media items, leaving them in the source albums.
+	KeepSources bool
+
+	// Location is the time zone of the dates of Split. The default is UTC.
+	Location *time.Location
+}
+
+// SplitPeriod is the period of the albums created by Reorganizer.Split.
+type SplitPeriod int
+
+// Periods of Reorganizer.Split.
+const (
+	SplitByDay SplitPeriod = iota
+	SplitByMonth
+)
+
+// Reorganization is the result of Reorganizer.Merge and Reorganizer.Split methods.
+type Reorganization struct {
+	// Targets are the new albums and their media items. With DryRun, their Album has no ID.
+	Targets []ReorganizedAlbum
+
+	// Unmovable are the media items that can't be moved.
+	Unmovable []UnmovableMediaItem
+
+	// Failed are the media items rejected by Albums.BatchAddMediaItems or Albums.BatchRemoveMediaItems.
+	Failed []BulkFailure
+}
+
+// ReorganizedAlbum represents a target album of a Reorganization.
+type ReorganizedAlbum struct {
+	Album        Album
+	MediaItemIDs []string
+}
+
+// UnmovableMediaItem represents a media item that can't be moved.
+type UnmovableMediaItem struct {
+	MediaItemID   string
+	SourceAlbumID string
+	Reason        string
+}
+
+// Reasons of UnmovableMediaItem.
+const (
+	UnmovableNotAppCreated     = "media item not created by the app"
+	UnmovableSourceNotAppOwned = "source album not created by the app"
+)
+
+type reorganizeMethods struct{}
+
+type reorganizeSource struct {
+	album Album
+	items []MediaItem
+}
+
+func (reorganizer reorganizeMethods) Merge(client *http.Client, sourceAlbumIDs []string, title string, options ReorganizeOptions) (Reorganization, error) {
+	sources, appCreated, err := readReorganizeSources(client, sourceAlbumIDs)
+	if err != nil {
+		return Reorganization{}, err
+	}
+	return reorganize(client, sources, appCreated, options, func(MediaItem) string { return title })
+}
+
+func (reorganizer reorganizeMethods) Split(client *http.Client, sourceAlbumID string, period SplitPeriod, options ReorganizeOptions) (Reorganization, error) {
+	sources, appCreated, err := readReorganizeSources(client, []string{sourceAlbumID})
+	if err != nil {
+		return Reorganization{}, err
+	}
+	loc := options.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	layout := "2006-01-02"
+	if period == SplitByMonth {
+		layout = "2006-01"
+	}
+	prefix := sources[0].album.Title
+	return reorganize(client, sources, appCreated, options, func(item MediaItem) string {
+		return prefix + " " + item.MediaMetadata.CreationTime.In(loc).Format(layout)
+	})
+}
+
+// readReorganizeSources reads the source albums with their media items, and the IDs of the media items created by the app.
+func readReorganizeSources(client *http.Client, albumIDs []string) ([]reorganizeSource, map[string]bool, error) {
+	var sources []reorganizeSource
+	for _, albumID := range albumIDs {
+		resp, err := Albums.Get(client, albumID)
+		if err != nil {
+			return nil, nil, err
+		}
+		source := reorganizeSource{album: Album(resp)}
+		err = searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: albumID}, func(item MediaItem) error {
+			source.items = append(source.items, item)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	appCreated, err := appCreatedMediaItemIDs(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sources, appCreated, nil
+}
+
+func reorganize(client *http.Client, sources []reorganizeSource, appCreated map[string]bool, options ReorganizeOptions, target func(MediaItem) string) (Reorganization, error) {
+	var result Reorganization
+
+	byTitle := map[string]*ReorganizedAlbum{}
+	var titles []string
+	// removals are the media items to remove from each source album once they are in their target.
+	removals := map[string]map[string]bool{}
+	seen := map[string]bool{}
+	for _, source := range sources {
+		for _, item := range source.items {
+			if !appCreated[item.ID] {
+				result.Unmovable = append(result.Unmovable, UnmovableMediaItem{MediaItemID: item.ID, SourceAlbumID: source.album.ID, Reason: UnmovableNotAppCreated})
+				continue
+			}
+			if !options.KeepSources {
+				if !source.album.IsWriteable {
+					result.Unmovable = append(result.Unmovable, UnmovableMediaItem{MediaItemID: item.ID, SourceAlbumID: source.album.ID, Reason: UnmovableSourceNotAppOwned})
+					continue
+				}
+				if removals[source.album.ID] == nil {
+					removals[source.album.ID] = map[string]bool{}
+				}
+				removals[source.album.ID][item.ID] = true
+			}
+			if seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			title := target(item)
+			if byTitle[title] == nil {
+				byTitle[title] = &ReorganizedAlbum{Album: Album{Title: title}}
+				titles = append(titles, title)
+			}
+			byTitle[title].MediaItemIDs = append(byTitle[title].MediaItemIDs, item.ID)
+		}
+	}
+	sort.Strings(titles)
+
+	added := map[string]bool{}
+	for _, title := range titles {
+		reorganized := byTitle[title]
+		if !options.DryRun {
+			album, err := createAlbum(client, title)
+			if err != nil {
+				return result, err
+			}
+			reorganized.Album = album
+			bulk, _ := BulkAddMediaItems(client, album.ID, reorganized.MediaItemIDs, BulkOptions{})
+			result.Failed = append(result.Failed, bulk.Failed...)
+			for _, id := range bulk.Succeeded {
+				added[id] = true
+			}
+		}
+		result.Targets = append(result.Targets, *reorganized)
+	}
+	if options.DryRun {
+		return result, nil
+	}
+
+	for _, source := range sources {
+		var ids []string
+		for _, item := range source.items {
+			if removals[source.album.ID][item.ID] && added[item.ID] {
+				ids = append(ids, item.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		bulk, _ := BulkRemoveMediaItems(client, source.album.ID, ids, BulkOptions{})
+		result.Failed = append(result.Failed, bulk.Failed...)
+	}
+	return result, nil
+}
@@ -0,0 +1,303 @@
+package gphotos
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reports is the only instance of ReportMethods(https://godoc.org/github.com/Q-Brains/gphotos#ReportMethods).
+var Reports ReportMethods = reportMethods{}
+
+// ReportMethods is a collection of methods that compute and write statistics of a library.
+// The only instance of ReportMethods is Reports(https://godoc.org/github.com/Q-Brains/gphotos#Reports).
+type ReportMethods interface {
+	// Generate is a method that scans the library with MediaItems.List and Albums.List and computes a LibraryReport.
+	Generate(client *http.Client) (LibraryReport, error)
+
+	// WriteJSON is a method that writes a LibraryReport as JSON.
+	WriteJSON(report LibraryReport, w io.Writer) error
+
+	// WriteCSV is a method that writes a LibraryReport as CSV with the columns "section", "key" and "count".
+	WriteCSV(report LibraryReport, w io.Writer) error
+
+	// WriteHTML is a method that writes a LibraryReport as a self-contained HTML page with inline SVG charts.
+	WriteHTML(report LibraryReport, w io.Writer) error
+}
+
+// LibraryReport represents statistics of a library.
+type LibraryReport struct {
+	GeneratedAt     time.Time    `json:"generatedAt"`
+	MediaItems      int          `json:"mediaItems"`
+	Photos          int          `json:"photos"`
+	Videos          int          `json:"videos"`
+	PhotoVideoRatio float64      `json:"photoVideoRatio"`
+	ByYear          []CountEntry `json:"byYear"`
+	ByMonth         []CountEntry `json:"byMonth"`
+	ByCameraMake    []CountEntry `json:"byCameraMake"`
+	ByCameraModel   []CountEntry `json:"byCameraModel"`
+	ByMimeType      []CountEntry `json:"byMimeType"`
+	ByResolution    []CountEntry `json:"byResolution"`
+	ByAlbum         []CountEntry `json:"byAlbum"`
+	LargestAlbums   []Album      `json:"largestAlbums"`
+}
+
+// CountEntry is a count of media items for a key.
+type CountEntry struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Number of albums in LibraryReport.LargestAlbums.
+const reportLargestAlbums = 10
+
+// Resolution buckets of LibraryReport.ByResolution in megapixels.
+var reportResolutions = []struct {
+	key string
+	max float64
+}{
+	{"unknown", 0},
+	{"<1MP", 1},
+	{"1-2MP", 2},
+	{"2-5MP", 5},
+	{"5-8MP", 8},
+	{"8-12MP", 12},
+	{"12-20MP", 20},
+	{"20-50MP", 50},
+	{">=50MP", -1},
+}
+
+type reportMethods struct{}
+
+func (reports reportMethods) Generate(client *http.Client) (LibraryReport, error) {
+	report := LibraryReport{GeneratedAt: time.Now().UTC()}
+	byYear, byMonth := map[string]int{}, map[string]int{}
+	byMake, byModel, byMime := map[string]int{}, map[string]int{}, map[string]int{}
+	byResolution := map[string]int{}
+
+	err := listAllMediaItems(client, func(item MediaItem) error {
+		report.MediaItems++
+		switch {
+		case strings.HasPrefix(item.MimeType, "image/"):
+			report.Photos++
+		case strings.HasPrefix(item.MimeType, "video/"):
+			report.Videos++
+		}
+		if t := item.MediaMetadata.CreationTime; !t.IsZero() {
+			byYear[t.Format("2006")]++
+			byMonth[t.Format("2006-01")]++
+		}
+		byMake[reportKey(item.MediaMetadata.CameraMake())]++
+		byModel[reportKey(strings.TrimSpace(item.MediaMetadata.CameraMake()+" "+item.MediaMetadata.CameraModel()))]++
+		byMime[reportKey(item.MimeType)]++
+		byResolution[resolutionBucket(item.MediaMetadata.Megapixels())]++
+		return nil
+	})
+	if err != nil {
+		return LibraryReport{}, err
+	}
+
+	var albums []Album
+	err = listAllAlbums(client, func(album Album) error {
+		albums = append(albums, album)
+		return nil
+	})
+	if err != nil {
+		return LibraryReport{}, err
+	}
+	sort.SliceStable(albums, func(i, j int) bool { return albums[i].MediaItemsCount > albums[j].MediaItemsCount })
+	for _, album := range albums {
+		report.ByAlbum = append(report.ByAlbum, CountEntry{Key: album.Title, Count: int(album.MediaItemsCount)})
+	}
+	if len(albums) > reportLargestAlbums {
+		report.LargestAlbums = albums[:reportLargestAlbums]
+	} else {
+		report.LargestAlbums = albums
+	}
+
+	if report.Videos > 0 {
+		report.PhotoVideoRatio = float64(report.Photos) / float64(report.Videos)
+	}
+	report.ByYear = sortedCountsByKey(byYear)
+	report.ByMonth = sortedCountsByKey(byMonth)
+	report.ByCameraMake = sortedCountsByCount(byMake)
+	report.ByCameraModel = sortedCountsByCount(byModel)
+	report.ByMimeType = sortedCountsByCount(byMime)
+	for _, bucket := range reportResolutions {
+		if n := byResolution[bucket.key]; n > 0 {
+			report.ByResolution = append(report.ByResolution, CountEntry{Key: bucket.key, Count: n})
+		}
+	}
+	return report, nil
+}
+
+func reportKey(key string) string {
+	if key == "" {
+		return "unknown"
+	}
+	return key
+}
+
+func resolutionBucket(megapixels float64) string {
+	if megapixels == 0 {
+		return "unknown"
+	}
+	for _, bucket := range reportResolutions[1:] {
+		if bucket.max < 0 || megapixels < bucket.max {
+			return bucket.key
+		}
+	}
+	return reportResolutions[len(reportResolutions)-1].key
+}
+
+func sortedCountsByKey(counts map[string]int) []CountEntry {
+	entries := make([]CountEntry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, CountEntry{Key: key, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+func sortedCountsByCount(counts map[string]int) []CountEntry {
+	entries := sortedCountsByKey(counts)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Count > entries[j].Count })
+	return entries
+}
+
+func (report LibraryReport) sections() []reportSection {
+	return []reportSection{
+		{"year", "Media items per year", report.ByYear},
+		{"month", "Media items per month", report.ByMonth},
+		{"cameraMake", "Camera makes", report.ByCameraMake},
+		{"cameraModel", "Camera models", report.ByCameraModel},
+		{"mimeType", "MIME types", report.ByMimeType},
+		{"resolution", "Resolutions", report.ByResolution},
+		{"album", "Albums", report.ByAlbum},
+	}
+}
+
+type reportSection struct {
+	Name    string
+	Title   string
+	Entries []CountEntry
+}
+
+func (reports reportMethods) WriteJSON(report LibraryReport, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func (reports reportMethods) WriteCSV(report LibraryReport, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"section", "key", "count"},
+		{"total", "mediaItems", strconv.Itoa(report.MediaItems)},
+		{"total", "photos", strconv.Itoa(report.Photos)},
+		{"total", "videos", strconv.Itoa(report.Videos)},
+		{"total", "photoVideoRatio", strconv.FormatFloat(report.PhotoVideoRatio, 'f', 3, 64)},
+	}
+	for _, section := range report.sections() {
+		for _, entry := range section.Entries {
+			rows = append(rows, []string{section.Name, entry.Key, strconv.Itoa(entry.Count)})
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+type reportChart struct {
+	Title  string
+	Height int
+	Bars   []reportBar
+}
+
+type reportBar struct {
+	Label string
+	Count int
+	Y     int
+	Width int
+}
+
+// Layout of the bar charts in pixels.
+const (
+	reportBarHeight = 18
+	reportBarWidth  = 480
+	reportMaxBars   = 40
+)
+
+func (reports reportMethods) WriteHTML(report LibraryReport, w io.Writer) error {
+	var charts []reportChart
+	for _, section := range report.sections() {
+		entries := section.Entries
+		if len(entries) == 0 {
+			continue
+		}
+		if len(entries) > reportMaxBars && section.Name != "month" {
+			entries = entries[:reportMaxBars]
+		}
+		max := 0
+		for _, entry := range entries {
+			if entry.Count > max {
+				max = entry.Count
+			}
+		}
+		chart := reportChart{Title: section.Title, Height: len(entries) * reportBarHeight}
+		for i, entry := range entries {
+			width := 0
+			if max > 0 {
+				width = entry.Count * reportBarWidth / max
+			}
+			chart.Bars = append(chart.Bars, reportBar{Label: entry.Key, Count: entry.Count, Y: i * reportBarHeight, Width: width})
+		}
+		charts = append(charts, chart)
+	}
+
+	return reportTemplate.Execute(w, struct {
+		Report LibraryReport
+		Charts []reportChart
+	}{report, charts})
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Google Photos library report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #202124; }
+table { border-collapse: collapse; }
+td, th { padding: 2px 12px; text-align: left; }
+svg text { font-size: 12px; }
+rect { fill: #1a73e8; }
+</style>
+</head>
+<body>
+<h1>Google Photos library report</h1>
+<p>Generated at {{.Report.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+<table>
+<tr><th>Media items</th><td>{{.Report.MediaItems}}</td></tr>
+<tr><th>Photos</th><td>{{.Report.Photos}}</td></tr>
+<tr><th>Videos</th><td>{{.Report.Videos}}</td></tr>
+<tr><th>Photos per video</th><td>{{printf "%.2f" .Report.PhotoVideoRatio}}</td></tr>
+</table>
+{{with .Report.LargestAlbums}}<h2>Largest albums</h2>
+<table>
+{{range .}}<tr><td>{{.Title}}</td><td>{{.MediaItemsCount}}</td></tr>
+{{end}}</table>
+{{end}}{{range .Charts}}<h2>{{.Title}}</h2>
+<svg xmlns="http://www.w3.org/2000/svg" width="840" height="{{.Height}}">
+{{range .Bars}}<text x="0" y="{{.Y}}" dy="13">{{.Label}}</text><rect x="260" y="{{.Y}}" width="{{.Width}}" height="15"></rect><text x="{{.Width}}" y="{{.Y}}" dx="266" dy="13">{{.Count}}</text>
+{{end}}</svg>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,265 @@
+package gphotos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Limits of MediaItems.Search documented in the API reference.
+// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search
+const (
+	maxSearchDates              = 5
+	maxSearchRanges             = 5
+	maxSearchIncludedCategories = 10
+)
+
+// SearchBuilder builds a MediaItemsSearchRequest and validates it before it is sent.
+// Use NewSearch to create a SearchBuilder.
+type SearchBuilder struct {
+	request  MediaItemsSearchRequest
+	included []ContentCategory
+	excluded []ContentCategory
+}
+
+// NewSearch returns an empty SearchBuilder.
+func NewSearch() *SearchBuilder {
+	return &SearchBuilder{}
+}
+
+// SearchRequestError is the error returned when a MediaItemsSearchRequest violates the constraints of MediaItems.Search.
+type SearchRequestError struct {
+	Problems []string
+}
+
+func (err *SearchRequestError) Error() string {
+	return "invalid MediaItemsSearchRequest: " + strings.Join(err.Problems, "; ")
+}
+
+// InAlbum limits the search to the album. It can't be combined with filters.
+func (builder *SearchBuilder) InAlbum(albumID string) *SearchBuilder {
+	builder.request.AlbumID = albumID
+	return builder
+}
+
+// PageSize sets the maximum number of media items returned per page. It must be 100 or less.
+func (builder *SearchBuilder) PageSize(size int) *SearchBuilder {
+	builder.request.PageSize = size
+	return builder
+}
+
+// PageToken sets the token of the page to be returned.
+func (builder *SearchBuilder) PageToken(token string) *SearchBuilder {
+	builder.request.PageToken = token
+	return builder
+}
+
+// OnDates adds dates to the date filter. Up to 5 dates can be added.
+func (builder *SearchBuilder) OnDates(dates ...Date) *SearchBuilder {
+	builder.request.Filters.DateFilter.Dates = append(builder.request.Filters.DateFilter.Dates, dates...)
+	return builder
+}
+
+// InRange adds an inclusive range of dates to the date filter. Up to 5 ranges can be added.
+func (builder *SearchBuilder) InRange(start Date, end Date) *SearchBuilder {
+	builder.request.Filters.DateFilter.Ranges = append(builder.request.Filters.DateFilter.Ranges, DateRange{StartDate: start, EndDate: end})
+	return builder
+}
+
+// Between adds the range of days from start to end to the date filter.
+func (builder *SearchBuilder) Between(start time.Time, end time.Time) *SearchBuilder {
+	return builder.InRange(DateOf(start), DateOf(end))
+}
+
+// IncludeCategories adds categories that media items must belong to. Up to 10 categories can be included.
+func (builder *SearchBuilder) IncludeCategories(categories ...ContentCategory) *SearchBuilder {
+	builder.included = append(builder.included, categories...)
+	return builder
+}
+
+// ExcludeCategories adds categories that media items must not belong to.
+func (builder *SearchBuilder) ExcludeCategories(categories ...ContentCategory) *SearchBuilder {
+	builder.excluded = append(builder.excluded, categories...)
+	return builder
+}
+
+// OfMediaType limits the search to a media type. Only one media type can be specified.
+func (builder *SearchBuilder) OfMediaType(mediaType MediaType) *SearchBuilder {
+	builder.request.Filters.MediaTypeFilter.MediaTypes = append(builder.request.Filters.MediaTypeFilter.MediaTypes, mediaType)
+	return builder
+}
+
+// OnlyFavorites limits the search to media items marked as favorites.
+func (builder *SearchBuilder) OnlyFavorites() *SearchBuilder {
+	if len(builder.request.Filters.FeatureFilter.IncludedFeatures) == 0 {
+		builder.request.Filters.FeatureFilter.IncludedFeatures = []Feature{Favorites}
+	}
+	return builder
+}
+
+// IncludeArchived includes archived media items in the search.
+func (builder *SearchBuilder) IncludeArchived() *SearchBuilder {
+	builder.request.Filters.IncludeArchivedMedia = true
+	return builder
+}
+
+// OnlyAppCreated limits the search to media items created by this app.
+func (builder *SearchBuilder) OnlyAppCreated() *SearchBuilder {
+	builder.request.Filters.ExcludeNonAppCreatedData = true
+	return builder
+}
+
+// Build returns the MediaItemsSearchRequest, or a *SearchRequestError if it violates the constraints of MediaItems.Search.
+func (builder *SearchBuilder) Build() (MediaItemsSearchRequest, error) {
+	request := builder.request
+	request.Filters.ContentFilter.IncludedContentCategories = appendCategories(nil, builder.included)
+	request.Filters.ContentFilter.ExcludedContentCategories = appendCategories(nil, builder.excluded)
+	if err := ValidateSearchRequest(request); err != nil {
+		return MediaItemsSearchRequest{}, err
+	}
+	return request, nil
+}
+
+// ValidateSearchRequest checks a MediaItemsSearchRequest against the constraints of MediaItems.Search.
+// It returns a *SearchRequestError describing every violation, or nil.
+func ValidateSearchRequest(request MediaItemsSearchRequest) error {
+	var problems []string
+	if request.AlbumID != "" && !request.Filters.isEmpty() {
+		problems = append(problems, "AlbumID can't be combined with Filters")
+	}
+	if request.PageSize < 0 || request.PageSize > maxMediaItemsPageSize {
+		problems = append(problems, fmt.Sprintf("PageSize must be from 0 to %d, got %d", maxMediaItemsPageSize, request.PageSize))
+	}
+
+	dates := request.Filters.DateFilter
+	if len(dates.Dates) > maxSearchDates {
+		problems = append(problems, fmt.Sprintf("up to %d dates can be specified, got %d", maxSearchDates, len(dates.Dates)))
+	}
+	if len(dates.Ranges) > maxSearchRanges {
+		problems = append(problems, fmt.Sprintf("up to %d date ranges can be specified, got %d", maxSearchRanges, len(dates.Ranges)))
+	}
+	for _, date := range dates.Dates {
+		if problem := date.problem(); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	for _, r := range dates.Ranges {
+		start, end := r.StartDate.problem(), r.EndDate.problem()
+		if start != "" {
+			problems = append(problems, "start of range: "+start)
+		}
+		if end != "" {
+			problems = append(problems, "end of range: "+end)
+		}
+		if start == "" && end == "" && r.StartDate.Year != 0 && r.EndDate.Year != 0 && r.EndDate.before(r.StartDate) {
+			problems = append(problems, fmt.Sprintf("range %s..%s ends before it starts", r.StartDate, r.EndDate))
+		}
+	}
+
+	content := request.Filters.ContentFilter
+	if len(content.IncludedContentCategories) > maxSearchIncludedCategories {
+		problems = append(problems, fmt.Sprintf("up to %d content categories can be included, got %d", maxSearchIncludedCategories, len(content.IncludedContentCategories)))
+	}
+	for _, category := range content.IncludedContentCategories {
+		if category < ContentCategoryNone || category > Holidays {
+			problems = append(problems, "unknown content category "+category.String())
+		}
+		for _, excluded := range content.ExcludedContentCategories {
+			if category == excluded {
+				problems = append(problems, "content category "+category.String()+" is both included and excluded")
+			}
+		}
+	}
+	for _, category := range content.ExcludedContentCategories {
+		if category < ContentCategoryNone || category > Holidays {
+			problems = append(problems, "unknown content category "+category.String())
+		}
+	}
+
+	types := request.Filters.MediaTypeFilter.MediaTypes
+	if len(types) > 1 {
+		problems = append(problems, fmt.Sprintf("only one media type can be specified, got %d", len(types)))
+	}
+	for _, t := range types {
+		if t < AllMedia || t > PhotoType {
+			problems = append(problems, "unknown media type "+t.String())
+		}
+	}
+	for _, f := range request.Filters.FeatureFilter.IncludedFeatures {
+		if f < FeatureNone || f > Favorites {
+			problems = append(problems, "unknown feature "+f.String())
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SearchRequestError{Problems: problems}
+	}
+	return nil
+}
+
+func (filters Filters) isEmpty() bool {
+	return len(filters.DateFilter.Dates) == 0 &&
+		len(filters.DateFilter.Ranges) == 0 &&
+		len(filters.ContentFilter.IncludedContentCategories) == 0 &&
+		len(filters.ContentFilter.ExcludedContentCategories) == 0 &&
+		len(filters.MediaTypeFilter.MediaTypes) == 0 &&
+		len(filters.FeatureFilter.IncludedFeatures) == 0 &&
+		!filters.IncludeArchivedMedia &&
+		!filters.ExcludeNonAppCreatedData
+}
+
+// appendCategories appends the categories that dst doesn't contain yet.
+func appendCategories(dst []ContentCategory, categories []ContentCategory) []ContentCategory {
+	for _, category := range categories {
+		found := false
+		for _, c := range dst {
+			if c == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, category)
+		}
+	}
+	return dst
+}
+
+// DateOf returns the Date of t in its location.
+func DateOf(t time.Time) Date {
+	return Date{Year: t.Year(), Month: int(t.Month()), Day: t.Day()}
+}
+
+func (date Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", date.Year, date.Month, date.Day)
+}
+
+// problem describes why the Date is invalid, or returns "".
+// Source: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search#date
+func (date Date) problem() string {
+	switch {
+	case date.Year < 0 || date.Year > 9999:
+		return fmt.Sprintf("year of %s must be from 1 to 9999, or 0", date)
+	case date.Month < 0 || date.Month > 12:
+		return fmt.Sprintf("month of %s must be from 1 to 12, or 0", date)
+	case date.Day < 0 || date.Day > 31:
+		return fmt.Sprintf("day of %s must be from 1 to 31, or 0", date)
+	case date.Month == 0 && date.Day != 0:
+		return fmt.Sprintf("%s has a day without a month", date)
+	case date.Year == 0 && date.Month == 0:
+		return "date must have a year or a month"
+	case date.Day != 0 && date.Year != 0 && time.Date(date.Year, time.Month(date.Month), date.Day, 0, 0, 0, 0, time.UTC).Day() != date.Day:
+		return fmt.Sprintf("%s is not a valid day", date)
+	}
+	return ""
+}
+
+func (date Date) before(other Date) bool {
+	if date.Year != other.Year {
+		return date.Year < other.Year
+	}
+	if date.Month != other.Month {
+		return date.Month < other.Month
+	}
+	return date.Day < other.Day
+}
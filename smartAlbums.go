@@ -0,0 +1,218 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SmartAlbums is the only instance of SmartAlbumMethods(https://godoc.org/github.com/Q-Brains/gphotos#SmartAlbumMethods).
+var SmartAlbums SmartAlbumMethods = smartAlbumMethods{}
+
+// SmartAlbumMethods is a collection of methods that keep albums in sync with saved searches.
+// The only instance of SmartAlbumMethods is SmartAlbums(https://godoc.org/github.com/Q-Brains/gphotos#SmartAlbums).
+type SmartAlbumMethods interface {
+	// Load is a method that reads SmartAlbum definitions from a JSON file.
+	Load(filePath string) ([]SmartAlbum, error)
+
+	// Save is a method that writes SmartAlbum definitions to a JSON file.
+	Save(filePath string, definitions []SmartAlbum) error
+
+	// Run is a method that evaluates each definition in the file and syncs its results into its album
+	// with BulkAddMediaItems and BulkRemoveMediaItems.
+	// Only media items created by the app can be added to an album, so the search of each definition is limited to them,
+	// and the other media items are left out instead of being rejected on every run.
+	// Media items the API still refuses are reported in SmartAlbumChange.Rejected.
+	// Albums are created for definitions without AlbumID, and their IDs are saved back into the file.
+	Run(client *http.Client, filePath string) (SmartAlbumRun, error)
+}
+
+// SmartAlbum is the definition of an album maintained by a saved search.
+// Media items can only be added to albums created by the app, so AlbumID must be such an album.
+type SmartAlbum struct {
+	Name    string                  `json:"name"`
+	AlbumID string                  `json:"albumId,omitempty"`
+	Request MediaItemsSearchRequest `json:"request"`
+}
+
+// SmartAlbumRun is the change log of SmartAlbums.Run method.
+type SmartAlbumRun struct {
+	RanAt   time.Time          `json:"ranAt"`
+	Changes []SmartAlbumChange `json:"changes,omitempty"`
+}
+
+// SmartAlbumChange represents the changes made to the album of a SmartAlbum.
+type SmartAlbumChange struct {
+	Name    string   `json:"name"`
+	AlbumID string   `json:"albumId"`
+	Created bool     `json:"created,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+
+	Rejected []SmartAlbumRejection `json:"rejected,omitempty"`
+}
+
+// SmartAlbumRejection represents a media item that couldn't be added to or removed from the album of a SmartAlbum.
+type SmartAlbumRejection struct {
+	MediaItemID string `json:"mediaItemId"`
+	Reason      string `json:"reason"`
+}
+
+// Report returns a human-readable change log of the SmartAlbumRun.
+func (run SmartAlbumRun) Report() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Smart albums run at %s\n", run.RanAt.Format(time.RFC3339))
+	for _, change := range run.Changes {
+		fmt.Fprintf(&sb, "%s (%s)", change.Name, change.AlbumID)
+		if change.Created {
+			sb.WriteString(" created")
+		}
+		fmt.Fprintf(&sb, ": %d added, %d removed, %d rejected\n", len(change.Added), len(change.Removed), len(change.Rejected))
+		for _, id := range change.Added {
+			fmt.Fprintf(&sb, "  + %s\n", id)
+		}
+		for _, id := range change.Removed {
+			fmt.Fprintf(&sb, "  - %s\n", id)
+		}
+		for _, rejection := range change.Rejected {
+			fmt.Fprintf(&sb, "  ! %s: %s\n", rejection.MediaItemID, rejection.Reason)
+		}
+	}
+	return sb.String()
+}
+
+type smartAlbumMethods struct{}
+
+func (smartAlbums smartAlbumMethods) Load(filePath string) ([]SmartAlbum, error) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []SmartAlbum
+	if err := json.Unmarshal(b, &definitions); err != nil {
+		return nil, err
+	}
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return nil, errors.New("smart album without name in " + filePath)
+		}
+		if definition.Request.AlbumID != "" {
+			return nil, errors.New("smart album " + definition.Name + " searches an album")
+		}
+		if err := ValidateSearchRequest(definition.Request); err != nil {
+			return nil, err
+		}
+	}
+	return definitions, nil
+}
+
+func (smartAlbums smartAlbumMethods) Save(filePath string, definitions []SmartAlbum) error {
+	b, err := json.MarshalIndent(definitions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filePath+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filePath+".tmp", filePath)
+}
+
+func (smartAlbums smartAlbumMethods) Run(client *http.Client, filePath string) (SmartAlbumRun, error) {
+	run := SmartAlbumRun{RanAt: time.Now().UTC()}
+	definitions, err := smartAlbums.Load(filePath)
+	if err != nil {
+		return run, err
+	}
+
+	created := false
+	for i := range definitions {
+		change, err := syncSmartAlbum(client, definitions[i])
+		if change.Created {
+			definitions[i].AlbumID = change.AlbumID
+			created = true
+		}
+		if change.Created || len(change.Added) > 0 || len(change.Removed) > 0 || len(change.Rejected) > 0 {
+			run.Changes = append(run.Changes, change)
+		}
+		if err != nil {
+			if created {
+				smartAlbums.Save(filePath, definitions)
+			}
+			return run, err
+		}
+	}
+	if created {
+		return run, smartAlbums.Save(filePath, definitions)
+	}
+	return run, nil
+}
+
+func syncSmartAlbum(client *http.Client, definition SmartAlbum) (SmartAlbumChange, error) {
+	change := SmartAlbumChange{Name: definition.Name, AlbumID: definition.AlbumID}
+
+	var current []string
+	if change.AlbumID == "" {
+		album, err := createAlbum(client, definition.Name)
+		if err != nil {
+			return change, err
+		}
+		change.AlbumID = album.ID
+		change.Created = true
+	} else {
+		err := searchAllMediaItems(client, MediaItemsSearchRequest{AlbumID: change.AlbumID}, func(item MediaItem) error {
+			current = append(current, item.ID)
+			return nil
+		})
+		if err != nil {
+			return change, err
+		}
+	}
+
+	// The API rejects filters combined with an album, so the media items of an album are checked against the app-created ones.
+	request := definition.Request
+	var appCreated map[string]bool
+	if request.AlbumID == "" {
+		request.Filters.ExcludeNonAppCreatedData = true
+	} else {
+		var err error
+		if appCreated, err = appCreatedMediaItemIDs(client); err != nil {
+			return change, err
+		}
+	}
+	var wanted []string
+	err := searchAllMediaItems(client, request, func(item MediaItem) error {
+		if appCreated == nil || appCreated[item.ID] {
+			wanted = append(wanted, item.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return change, err
+	}
+
+	added, err := BulkAddMediaItems(client, change.AlbumID, subtractIDs(wanted, current), BulkOptions{})
+	if err != nil {
+		return change, err
+	}
+	change.Added = added.Succeeded
+	change.addRejected(added.Failed)
+
+	removed, err := BulkRemoveMediaItems(client, change.AlbumID, subtractIDs(current, wanted), BulkOptions{})
+	if err != nil {
+		return change, err
+	}
+	change.Removed = removed.Succeeded
+	change.addRejected(removed.Failed)
+	return change, nil
+}
+
+func (change *SmartAlbumChange) addRejected(failed []BulkFailure) {
+	for _, failure := range failed {
+		change.Rejected = append(change.Rejected, SmartAlbumRejection{MediaItemID: failure.MediaItemID, Reason: failure.Err.Error()})
+	}
+}
@@ -0,0 +1,306 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshots is the only instance of SnapshotMethods(https://godoc.org/github.com/Q-Brains/gphotos#SnapshotMethods).
+var Snapshots SnapshotMethods = snapshotMethods{}
+
+// SnapshotMethods is a collection of methods that record and compare the state of a library.
+// The only instance of SnapshotMethods is Snapshots(https://godoc.org/github.com/Q-Brains/gphotos#Snapshots).
+type SnapshotMethods interface {
+	// Take is a method that records albums from Albums.List and SharedAlbums.List, their media items from MediaItems.Search,
+	// and media items from MediaItems.List.
+	Take(client *http.Client) (Snapshot, error)
+
+	// Save is a method that writes a Snapshot to a file as JSON.
+	Save(snapshot Snapshot, filePath string) error
+
+	// Load is a method that reads a Snapshot written by Snapshots.Save.
+	Load(filePath string) (Snapshot, error)
+
+	// Diff is a method that returns the changes from one Snapshot to a later one.
+	Diff(from Snapshot, to Snapshot) Changeset
+}
+
+// Snapshot represents the state of a library at a point in time.
+type Snapshot struct {
+	TakenAt    time.Time                `json:"takenAt"`
+	Albums     map[string]SnapshotAlbum `json:"albums"`
+	MediaItems map[string]MediaItem     `json:"mediaItems"`
+}
+
+// SnapshotAlbum represents an album and its media items in a Snapshot.
+type SnapshotAlbum struct {
+	Album        Album    `json:"album"`
+	Shared       bool     `json:"shared,omitempty"`
+	MediaItemIDs []string `json:"mediaItemIds,omitempty"`
+}
+
+// Changeset represents the changes between two Snapshots.
+type Changeset struct {
+	NewMediaItems      []MediaItem           `json:"newMediaItems,omitempty"`
+	RemovedMediaItems  []MediaItem           `json:"removedMediaItems,omitempty"`
+	DescriptionChanges []DescriptionChange   `json:"descriptionChanges,omitempty"`
+	NewAlbums          []Album               `json:"newAlbums,omitempty"`
+	NewSharedAlbums    []Album               `json:"newSharedAlbums,omitempty"`
+	RemovedAlbums      []Album               `json:"removedAlbums,omitempty"`
+	ShareChanges       []ShareChange         `json:"shareChanges,omitempty"`
+	AddedToAlbums      []AlbumMembershipDiff `json:"addedToAlbums,omitempty"`
+	RemovedFromAlbums  []AlbumMembershipDiff `json:"removedFromAlbums,omitempty"`
+	OldTakenAt         time.Time             `json:"oldTakenAt"`
+	NewTakenAt         time.Time             `json:"newTakenAt"`
+}
+
+// DescriptionChange represents a changed description of a media item.
+type DescriptionChange struct {
+	MediaItemID string `json:"mediaItemId"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+}
+
+// ShareChange represents changed share settings of an album.
+type ShareChange struct {
+	Album Album     `json:"album"`
+	Old   ShareInfo `json:"old"`
+	New   ShareInfo `json:"new"`
+}
+
+// AlbumMembershipDiff represents media items added to or removed from an album.
+type AlbumMembershipDiff struct {
+	Album        Album    `json:"album"`
+	MediaItemIDs []string `json:"mediaItemIds"`
+}
+
+type snapshotMethods struct{}
+
+func (snapshots snapshotMethods) Take(client *http.Client) (Snapshot, error) {
+	snapshot := Snapshot{
+		TakenAt:    time.Now().UTC(),
+		Albums:     map[string]SnapshotAlbum{},
+		MediaItems: map[string]MediaItem{},
+	}
+
+	entries := map[string]*SnapshotAlbum{}
+	err := walkAlbums(client, true, func(album Album, shared bool) (func(MediaItem) error, error) {
+		entry := &SnapshotAlbum{Album: album, Shared: shared || album.ShareInfo != ShareInfo{}}
+		entries[album.ID] = entry
+		return func(item MediaItem) error {
+			entry.MediaItemIDs = append(entry.MediaItemIDs, item.ID)
+			// Media items of shared albums are not always listed by MediaItems.List.
+			snapshot.MediaItems[item.ID] = snapshotMediaItem(item)
+			return nil
+		}, nil
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for id, entry := range entries {
+		snapshot.Albums[id] = *entry
+	}
+
+	err = listAllMediaItems(client, func(item MediaItem) error {
+		snapshot.MediaItems[item.ID] = snapshotMediaItem(item)
+		return nil
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// snapshotMediaItem drops the fields that change on every request.
+func snapshotMediaItem(item MediaItem) MediaItem {
+	item.BaseURL = ""
+	item.ContributorInfo.ProfilePictureBaseURL = ""
+	return item
+}
+
+func (snapshots snapshotMethods) Save(snapshot Snapshot, filePath string) error {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, b, 0644)
+}
+
+func (snapshots snapshotMethods) Load(filePath string) (Snapshot, error) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (snapshots snapshotMethods) Diff(from Snapshot, to Snapshot) Changeset {
+	changes := Changeset{OldTakenAt: from.TakenAt, NewTakenAt: to.TakenAt}
+
+	for _, id := range sortedMediaItemIDs(to.MediaItems) {
+		item := to.MediaItems[id]
+		oldItem, ok := from.MediaItems[id]
+		if !ok {
+			changes.NewMediaItems = append(changes.NewMediaItems, item)
+		} else if oldItem.Description != item.Description {
+			changes.DescriptionChanges = append(changes.DescriptionChanges, DescriptionChange{
+				MediaItemID: id,
+				Old:         oldItem.Description,
+				New:         item.Description,
+			})
+		}
+	}
+	for _, id := range sortedMediaItemIDs(from.MediaItems) {
+		if _, ok := to.MediaItems[id]; !ok {
+			changes.RemovedMediaItems = append(changes.RemovedMediaItems, from.MediaItems[id])
+		}
+	}
+
+	for _, id := range sortedAlbumIDs(to.Albums) {
+		album := to.Albums[id]
+		oldAlbum, ok := from.Albums[id]
+		if !ok {
+			if album.Shared {
+				changes.NewSharedAlbums = append(changes.NewSharedAlbums, album.Album)
+			} else {
+				changes.NewAlbums = append(changes.NewAlbums, album.Album)
+			}
+			if len(album.MediaItemIDs) > 0 {
+				changes.AddedToAlbums = append(changes.AddedToAlbums, AlbumMembershipDiff{Album: album.Album, MediaItemIDs: album.MediaItemIDs})
+			}
+			continue
+		}
+		if oldAlbum.Album.ShareInfo != album.Album.ShareInfo {
+			changes.ShareChanges = append(changes.ShareChanges, ShareChange{
+				Album: album.Album,
+				Old:   oldAlbum.Album.ShareInfo,
+				New:   album.Album.ShareInfo,
+			})
+		}
+		if added := subtractIDs(album.MediaItemIDs, oldAlbum.MediaItemIDs); len(added) > 0 {
+			changes.AddedToAlbums = append(changes.AddedToAlbums, AlbumMembershipDiff{Album: album.Album, MediaItemIDs: added})
+		}
+		if removed := subtractIDs(oldAlbum.MediaItemIDs, album.MediaItemIDs); len(removed) > 0 {
+			changes.RemovedFromAlbums = append(changes.RemovedFromAlbums, AlbumMembershipDiff{Album: album.Album, MediaItemIDs: removed})
+		}
+	}
+	for _, id := range sortedAlbumIDs(from.Albums) {
+		if _, ok := to.Albums[id]; !ok {
+			changes.RemovedAlbums = append(changes.RemovedAlbums, from.Albums[id].Album)
+		}
+	}
+
+	return changes
+}
+
+// IsEmpty reports whether the Changeset contains no changes.
+func (changes Changeset) IsEmpty() bool {
+	return len(changes.NewMediaItems) == 0 &&
+		len(changes.RemovedMediaItems) == 0 &&
+		len(changes.DescriptionChanges) == 0 &&
+		len(changes.NewAlbums) == 0 &&
+		len(changes.NewSharedAlbums) == 0 &&
+		len(changes.RemovedAlbums) == 0 &&
+		len(changes.ShareChanges) == 0 &&
+		len(changes.AddedToAlbums) == 0 &&
+		len(changes.RemovedFromAlbums) == 0
+}
+
+// Report returns a human-readable report of the Changeset.
+func (changes Changeset) Report() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Changes from %s to %s\n", changes.OldTakenAt.Format(time.RFC3339), changes.NewTakenAt.Format(time.RFC3339))
+	if changes.IsEmpty() {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+
+	section := func(title string, n int) {
+		if n > 0 {
+			fmt.Fprintf(&sb, "\n%s (%d):\n", title, n)
+		}
+	}
+	section("New media items", len(changes.NewMediaItems))
+	for _, item := range changes.NewMediaItems {
+		fmt.Fprintf(&sb, "  + %s %s\n", item.ID, item.Filename)
+	}
+	section("Removed media items", len(changes.RemovedMediaItems))
+	for _, item := range changes.RemovedMediaItems {
+		fmt.Fprintf(&sb, "  - %s %s\n", item.ID, item.Filename)
+	}
+	section("Changed descriptions", len(changes.DescriptionChanges))
+	for _, change := range changes.DescriptionChanges {
+		fmt.Fprintf(&sb, "  ~ %s: %q -> %q\n", change.MediaItemID, change.Old, change.New)
+	}
+	section("New albums", len(changes.NewAlbums))
+	for _, album := range changes.NewAlbums {
+		fmt.Fprintf(&sb, "  + %s %q\n", album.ID, album.Title)
+	}
+	section("New shared albums", len(changes.NewSharedAlbums))
+	for _, album := range changes.NewSharedAlbums {
+		fmt.Fprintf(&sb, "  + %s %q\n", album.ID, album.Title)
+	}
+	section("Removed albums", len(changes.RemovedAlbums))
+	for _, album := range changes.RemovedAlbums {
+		fmt.Fprintf(&sb, "  - %s %q\n", album.ID, album.Title)
+	}
+	section("Changed share settings", len(changes.ShareChanges))
+	for _, change := range changes.ShareChanges {
+		fmt.Fprintf(&sb, "  ~ %q: collaborative %t -> %t, commentable %t -> %t, shareable URL %q -> %q\n",
+			change.Album.Title,
+			change.Old.SharedAlbumOptions.IsCollaborative, change.New.SharedAlbumOptions.IsCollaborative,
+			change.Old.SharedAlbumOptions.IsCommentable, change.New.SharedAlbumOptions.IsCommentable,
+			change.Old.ShareableURL, change.New.ShareableURL)
+	}
+	section("Items added to albums", len(changes.AddedToAlbums))
+	for _, diff := range changes.AddedToAlbums {
+		fmt.Fprintf(&sb, "  %q: +%d (%s)\n", diff.Album.Title, len(diff.MediaItemIDs), strings.Join(diff.MediaItemIDs, ", "))
+	}
+	section("Items removed from albums", len(changes.RemovedFromAlbums))
+	for _, diff := range changes.RemovedFromAlbums {
+		fmt.Fprintf(&sb, "  %q: -%d (%s)\n", diff.Album.Title, len(diff.MediaItemIDs), strings.Join(diff.MediaItemIDs, ", "))
+	}
+	return sb.String()
+}
+
+// subtractIDs returns the IDs of a that are not contained in b.
+func subtractIDs(a []string, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, id := range b {
+		set[id] = true
+	}
+	var result []string
+	for _, id := range a {
+		if !set[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func sortedMediaItemIDs(items map[string]MediaItem) []string {
+	ids := make([]string, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedAlbumIDs(albums map[string]SnapshotAlbum) []string {
+	ids := make([]string, 0, len(albums))
+	for id := range albums {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,158 @@
+package gphotos
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+)
+
+// Storyboards is the only instance of StoryboardMethods(https://godoc.org/github.com/Q-Brains/gphotos#StoryboardMethods).
+var Storyboards StoryboardMethods = storyboardMethods{}
+
+// StoryboardMethods is a collection of methods that assemble narrative albums.
+// The only instance of StoryboardMethods is Storyboards(https://godoc.org/github.com/Q-Brains/gphotos#Storyboards).
+type StoryboardMethods interface {
+	// Materialize is a method that appends the blocks to the end of an album in order.
+	// Media blocks are uploaded and created with MediaItems.BatchCreate and an AlbumPosition,
+	// and the other blocks are added with Albums.AddEnrichment after the previous block.
+	// The album must have been created by the app.
+	// On error, the returned Storyboard contains the blocks materialized so far.
+	Materialize(client *http.Client, albumID string, blocks []StoryboardBlock) (Storyboard, error)
+}
+
+// StoryboardBlock is a block of a storyboard. Use MediaBlock, TextBlock, LocationBlock or MapBlock to create one.
+type StoryboardBlock struct {
+	FilePath    string `json:"filePath,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Enrichment NewEnrichmentItem `json:"enrichment,omitempty"`
+}
+
+// MediaBlock returns a StoryboardBlock uploading the file with the description.
+func MediaBlock(filePath string, description string) StoryboardBlock {
+	return StoryboardBlock{FilePath: filePath, Description: description}
+}
+
+// TextBlock returns a StoryboardBlock adding a text header.
+func TextBlock(text string) StoryboardBlock {
+	return StoryboardBlock{Enrichment: NewEnrichmentItem{TextEnrichment: TextEnrichment{Text: text}}}
+}
+
+// LocationBlock returns a StoryboardBlock adding a location pin.
+func LocationBlock(location Location) StoryboardBlock {
+	return StoryboardBlock{Enrichment: NewEnrichmentItem{LocationEnrichment: LocationEnrichment{Location: location}}}
+}
+
+// MapBlock returns a StoryboardBlock adding a map from origin to destination.
+func MapBlock(origin Location, destination Location) StoryboardBlock {
+	return StoryboardBlock{Enrichment: NewEnrichmentItem{MapEnrichment: MapEnrichment{Origin: origin, Destination: destination}}}
+}
+
+func (block StoryboardBlock) isMedia() bool {
+	return block.FilePath != ""
+}
+
+// Storyboard is the result of Storyboards.Materialize method.
+type Storyboard struct {
+	AlbumID string            `json:"albumId"`
+	Blocks  []StoryboardEntry `json:"blocks,omitempty"`
+}
+
+// StoryboardEntry represents a materialized block. Either MediaItem or EnrichmentItemID is set.
+type StoryboardEntry struct {
+	Block            StoryboardBlock `json:"block"`
+	MediaItem        MediaItem       `json:"mediaItem,omitempty"`
+	EnrichmentItemID string          `json:"enrichmentItemId,omitempty"`
+}
+
+// EnrichmentItemIDs returns the IDs of the enrichments created for the Storyboard in order.
+func (storyboard Storyboard) EnrichmentItemIDs() []string {
+	var ids []string
+	for _, entry := range storyboard.Blocks {
+		if entry.EnrichmentItemID != "" {
+			ids = append(ids, entry.EnrichmentItemID)
+		}
+	}
+	return ids
+}
+
+type storyboardMethods struct{}
+
+func (storyboards storyboardMethods) Materialize(client *http.Client, albumID string, blocks []StoryboardBlock) (Storyboard, error) {
+	storyboard := Storyboard{AlbumID: albumID}
+	for i, block := range blocks {
+		enrichments := 0
+		for _, set := range []bool{
+			block.Enrichment.TextEnrichment != (TextEnrichment{}),
+			block.Enrichment.LocationEnrichment != (LocationEnrichment{}),
+			block.Enrichment.MapEnrichment != (MapEnrichment{}),
+		} {
+			if set {
+				enrichments++
+			}
+		}
+		if (block.isMedia() && enrichments != 0) || (!block.isMedia() && enrichments != 1) {
+			return storyboard, errors.New("storyboard block " + strconv.Itoa(i) + " must be exactly one of media, text, location or map")
+		}
+	}
+
+	position := AlbumPosition{Position: LastInAlbum}
+	for len(blocks) > 0 {
+		if !blocks[0].isMedia() {
+			block := blocks[0]
+			resp, err := Albums.AddEnrichment(client, albumID, AlbumsAddEnrichmentRequest{
+				NewEnrichmentItem: block.Enrichment,
+				AlbumPosition:     position,
+			})
+			if err != nil {
+				return storyboard, err
+			}
+			storyboard.Blocks = append(storyboard.Blocks, StoryboardEntry{Block: block, EnrichmentItemID: resp.EnrichmentItem.ID})
+			position = AlbumPosition{Position: AfterEnrichmentItem, RelativeEnrichmentItemID: resp.EnrichmentItem.ID}
+			blocks = blocks[1:]
+			continue
+		}
+
+		// Consecutive media blocks are created by one call.
+		n := 0
+		for n < len(blocks) && n < maxBatchSize && blocks[n].isMedia() {
+			n++
+		}
+		req := MediaItemsBatchCreateRequest{AlbumID: albumID, AlbumPosition: position}
+		for _, block := range blocks[:n] {
+			token, err := UploadingMedia.UploadMedia(client, block.FilePath, filepath.Base(block.FilePath))
+			if err != nil {
+				return storyboard, err
+			}
+			req.NewMediaItems = append(req.NewMediaItems, NewMediaItem{
+				Description:     block.Description,
+				SimpleMediaItem: SimpleMediaItem{UploadToken: token},
+			})
+		}
+		resp, err := MediaItems.BatchCreate(client, req)
+		if err != nil {
+			return storyboard, err
+		}
+		if len(resp.NewMediaItemResults) != n {
+			return storyboard, errors.New("MediaItems.BatchCreate returned " + strconv.Itoa(len(resp.NewMediaItemResults)) + " results for " + strconv.Itoa(n) + " media items")
+		}
+		// Every created media item is recorded before a failure is reported, so that a retry doesn't duplicate it.
+		var failure error
+		for j, result := range resp.NewMediaItemResults {
+			if result.Status.Message != "OK" {
+				if failure == nil {
+					failure = errors.New("NewMediaItemResult.Status.Message is not \"OK\": " + result.Status.Message)
+				}
+				continue
+			}
+			storyboard.Blocks = append(storyboard.Blocks, StoryboardEntry{Block: blocks[j], MediaItem: result.MediaItem})
+			position = AlbumPosition{Position: AfterMediaItem, RelativeMediaItemID: result.MediaItem.ID}
+		}
+		if failure != nil {
+			return storyboard, failure
+		}
+		blocks = blocks[n:]
+	}
+	return storyboard, nil
+}
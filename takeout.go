@@ -0,0 +1,409 @@
+package gphotos
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Takeout is the only instance of TakeoutMethods(https://godoc.org/github.com/Q-Brains/gphotos#TakeoutMethods).
+var Takeout TakeoutMethods = takeoutMethods{}
+
+// TakeoutMethods is a collection of methods that read Google Takeout archives and restore them to Google Photos.
+// The only instance of TakeoutMethods is Takeout(https://godoc.org/github.com/Q-Brains/gphotos#Takeout).
+// Source: https://support.google.com/accounts/answer/3024190
+type TakeoutMethods interface {
+	// Read is a method that reads media files and their `.json` sidecars from Takeout archives.
+	// Archives must be `.zip`, `.tgz` or `.tar.gz` files.
+	// Entries whose extension is not of a photo or video, such as the HTML index pages of Takeout, are skipped.
+	Read(archivePaths ...string) ([]TakeoutItem, error)
+
+	// Reconcile is a method that matches TakeoutItems against the library with MediaItems.Search.
+	// Items are matched by filename and creation time.
+	Reconcile(client *http.Client, items []TakeoutItem) (TakeoutReconciliation, error)
+
+	// Restore is a method that uploads TakeoutItems with their original descriptions.
+	// It is intended to be called with TakeoutReconciliation.Missing.
+	Restore(client *http.Client, items []TakeoutItem) ([]MediaItem, error)
+}
+
+// TakeoutItem represents a media file in a Takeout archive.
+type TakeoutItem struct {
+	ArchivePath string         `json:"archivePath,omitempty"`
+	Path        string         `json:"path,omitempty"`
+	MediaItem   MediaItem      `json:"mediaItem,omitempty"`
+	GeoData     TakeoutGeoData `json:"geoData,omitempty"`
+}
+
+// TakeoutGeoData represents the location recorded in a Takeout sidecar.
+type TakeoutGeoData struct {
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	Altitude  float64 `json:"altitude,omitempty"`
+}
+
+// TakeoutReconciliation is the result of the Takeout.Reconcile method.
+type TakeoutReconciliation struct {
+	Matched []TakeoutMatch `json:"matched,omitempty"`
+	Missing []TakeoutItem  `json:"missing,omitempty"`
+}
+
+// TakeoutMatch represents a TakeoutItem found in the library.
+type TakeoutMatch struct {
+	TakeoutItem TakeoutItem `json:"takeoutItem,omitempty"`
+	MediaItem   MediaItem   `json:"mediaItem,omitempty"`
+}
+
+type takeoutSidecar struct {
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	PhotoTakenTime takeoutTimestamp `json:"photoTakenTime"`
+	CreationTime   takeoutTimestamp `json:"creationTime"`
+	GeoData        TakeoutGeoData   `json:"geoData"`
+	GeoDataExif    TakeoutGeoData   `json:"geoDataExif"`
+}
+
+type takeoutTimestamp struct {
+	Timestamp string `json:"timestamp"`
+}
+
+func (timestamp takeoutTimestamp) time() (time.Time, bool) {
+	sec, err := strconv.ParseInt(timestamp.Timestamp, 10, 64)
+	if err != nil || sec == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0).UTC(), true
+}
+
+type takeoutMethods struct{}
+
+func (takeout takeoutMethods) Read(archivePaths ...string) ([]TakeoutItem, error) {
+	sidecars := map[string]takeoutSidecar{}
+	var items []TakeoutItem
+	for _, archivePath := range archivePaths {
+		err := walkTakeoutArchive(archivePath, func(name string, r io.Reader) error {
+			if strings.HasSuffix(name, "/") {
+				return nil
+			}
+			if strings.EqualFold(path.Ext(name), ".json") {
+				b, err := ioutil.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				var sidecar takeoutSidecar
+				if err := json.Unmarshal(b, &sidecar); err != nil {
+					// Takeout also contains JSON files that are not sidecars.
+					return nil
+				}
+				sidecars[name] = sidecar
+				return nil
+			}
+			mimeType := takeoutMimeType(name)
+			if !strings.HasPrefix(mimeType, "image/") && !strings.HasPrefix(mimeType, "video/") {
+				return nil
+			}
+			items = append(items, TakeoutItem{ArchivePath: archivePath, Path: name, MediaItem: MediaItem{MimeType: mimeType}})
+			return nil
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Sidecars are named after the media file, optionally with ".supplemental-metadata".
+	byMedia := map[string]takeoutSidecar{}
+	for name, sidecar := range sidecars {
+		base := strings.TrimSuffix(name, path.Ext(name))
+		base = strings.TrimSuffix(base, ".supplemental-metadata")
+		byMedia[base] = sidecar
+		if sidecar.Title != "" {
+			byMedia[path.Join(path.Dir(name), sidecar.Title)] = sidecar
+		}
+	}
+
+	for i := range items {
+		item := &items[i]
+		item.MediaItem.Filename = path.Base(item.Path)
+
+		sidecar, ok := byMedia[item.Path]
+		if !ok {
+			// Edited copies share the sidecar of the original.
+			ext := path.Ext(item.Path)
+			sidecar, ok = byMedia[strings.TrimSuffix(strings.TrimSuffix(item.Path, ext), "-edited")+ext]
+		}
+		if !ok {
+			continue
+		}
+		item.MediaItem.Description = sidecar.Description
+		if t, ok := sidecar.PhotoTakenTime.time(); ok {
+			item.MediaItem.MediaMetadata.CreationTime = t
+		} else if t, ok := sidecar.CreationTime.time(); ok {
+			item.MediaItem.MediaMetadata.CreationTime = t
+		}
+		item.GeoData = sidecar.GeoData
+		if item.GeoData.Latitude == 0 && item.GeoData.Longitude == 0 {
+			item.GeoData = sidecar.GeoDataExif
+		}
+	}
+
+	return items, nil
+}
+
+// takeoutMediaTypes are the MIME types of the photo and video formats of Google Photos,
+// since mime.TypeByExtension only knows a few of them without the system MIME tables.
+var takeoutMediaTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".heic": "image/heic",
+	".heif": "image/heif",
+	".avif": "image/avif",
+	".ico":  "image/x-icon",
+	".dng":  "image/x-adobe-dng",
+	".cr2":  "image/x-canon-cr2",
+	".nef":  "image/x-nikon-nef",
+	".arw":  "image/x-sony-arw",
+	".orf":  "image/x-olympus-orf",
+	".rw2":  "image/x-panasonic-rw2",
+	".mp4":  "video/mp4",
+	".m4v":  "video/x-m4v",
+	".mov":  "video/quicktime",
+	".3gp":  "video/3gpp",
+	".3g2":  "video/3gpp2",
+	".avi":  "video/x-msvideo",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".wmv":  "video/x-ms-wmv",
+	".asf":  "video/x-ms-asf",
+	".mpg":  "video/mpeg",
+	".mpeg": "video/mpeg",
+	".mts":  "video/mp2t",
+	".m2ts": "video/mp2t",
+	".mod":  "video/mpeg",
+	".tod":  "video/mpeg",
+}
+
+func takeoutMimeType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if mimeType, ok := takeoutMediaTypes[ext]; ok {
+		return mimeType
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// walkTakeoutArchive calls fn for each entry of the archive.
+// If only is not nil, fn is called only for the entries contained in it.
+func walkTakeoutArchive(archivePath string, fn func(name string, r io.Reader) error, only map[string]bool) error {
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if only != nil && !only[f.Name] {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = fn(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.HasSuffix(lower, ".tgz"), strings.HasSuffix(lower, ".tar.gz"):
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		gr, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		tr := tar.NewReader(gr)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if header.Typeflag != tar.TypeReg || (only != nil && !only[header.Name]) {
+				continue
+			}
+			if err := fn(header.Name, tr); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("unsupported Takeout archive: " + archivePath)
+	}
+}
+
+func (takeout takeoutMethods) Reconcile(client *http.Client, items []TakeoutItem) (TakeoutReconciliation, error) {
+	// Search the span of the creation times, or the whole library if some items have no creation time.
+	request := MediaItemsSearchRequest{}
+	var start, end time.Time
+	for _, item := range items {
+		t := item.MediaItem.MediaMetadata.CreationTime
+		if t.IsZero() {
+			start, end = time.Time{}, time.Time{}
+			break
+		}
+		if start.IsZero() || t.Before(start) {
+			start = t
+		}
+		if end.IsZero() || t.After(end) {
+			end = t
+		}
+	}
+	if !start.IsZero() {
+		// Allow a day of margin since the API compares dates in the local time of the media.
+		start, end = start.AddDate(0, 0, -1), end.AddDate(0, 0, 1)
+		request.Filters.DateFilter.Ranges = []DateRange{{
+			StartDate: DateOf(start),
+			EndDate:   DateOf(end),
+		}}
+	}
+
+	byFilename := map[string][]MediaItem{}
+	err := searchAllMediaItems(client, request, func(item MediaItem) error {
+		byFilename[item.Filename] = append(byFilename[item.Filename], item)
+		return nil
+	})
+	if err != nil {
+		return TakeoutReconciliation{}, err
+	}
+
+	var result TakeoutReconciliation
+	for _, item := range items {
+		if found, ok := matchTakeoutItem(item, byFilename[item.MediaItem.Filename]); ok {
+			result.Matched = append(result.Matched, TakeoutMatch{TakeoutItem: item, MediaItem: found})
+		} else {
+			result.Missing = append(result.Missing, item)
+		}
+	}
+	return result, nil
+}
+
+func matchTakeoutItem(item TakeoutItem, candidates []MediaItem) (MediaItem, bool) {
+	t := item.MediaItem.MediaMetadata.CreationTime
+	for _, candidate := range candidates {
+		if t.IsZero() || candidate.MediaMetadata.CreationTime.Unix() == t.Unix() {
+			return candidate, true
+		}
+	}
+	return MediaItem{}, false
+}
+
+func (takeout takeoutMethods) Restore(client *http.Client, items []TakeoutItem) ([]MediaItem, error) {
+	dir, err := ioutil.TempDir("", "gphotos-takeout")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	byArchive := map[string]map[string]bool{}
+	for _, item := range items {
+		if byArchive[item.ArchivePath] == nil {
+			byArchive[item.ArchivePath] = map[string]bool{}
+		}
+		byArchive[item.ArchivePath][item.Path] = true
+	}
+	archivePaths := make([]string, 0, len(byArchive))
+	for archivePath := range byArchive {
+		archivePaths = append(archivePaths, archivePath)
+	}
+	sort.Strings(archivePaths)
+
+	tokens := map[string]string{}
+	for _, archivePath := range archivePaths {
+		err := walkTakeoutArchive(archivePath, func(name string, r io.Reader) error {
+			tmp, err := ioutil.TempFile(dir, "media")
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(tmp, r)
+			tmp.Close()
+			if err != nil {
+				return err
+			}
+			token, err := UploadingMedia.UploadMedia(client, tmp.Name(), path.Base(name))
+			os.Remove(tmp.Name())
+			if err != nil {
+				return err
+			}
+			tokens[archivePath+"\x00"+name] = token
+			return nil
+		}, byArchive[archivePath])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var newItems []NewMediaItem
+	for _, item := range items {
+		token, ok := tokens[item.ArchivePath+"\x00"+item.Path]
+		if !ok {
+			return nil, errors.New("Takeout item not found in archive: " + item.Path)
+		}
+		newItems = append(newItems, NewMediaItem{
+			Description:     item.MediaItem.Description,
+			SimpleMediaItem: SimpleMediaItem{UploadToken: token},
+		})
+	}
+
+	var created []MediaItem
+	for len(newItems) > 0 {
+		n := len(newItems)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		resp, err := MediaItems.BatchCreate(client, MediaItemsBatchCreateRequest{NewMediaItems: newItems[:n]})
+		if err != nil {
+			return created, err
+		}
+		// Every created media item is returned before a failure is reported, so that a re-run doesn't upload it again.
+		var failure error
+		for _, result := range resp.NewMediaItemResults {
+			if result.Status.Message != "OK" {
+				if failure == nil {
+					failure = errors.New("NewMediaItemResult.Status.Message is not \"OK\": " + result.Status.Message)
+				}
+				continue
+			}
+			created = append(created, result.MediaItem)
+		}
+		if failure != nil {
+			return created, failure
+		}
+		newItems = newItems[n:]
+	}
+	return created, nil
+}
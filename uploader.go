@@ -16,14 +16,21 @@ var Uploader UploadMethods = uploadMethods{}
 type UploadMethods interface {
 	// Upload is a method to upload MediaItems to GooglePhotos.
 	// Use UploadWithAlbum or UploadWithAlbumname if you want to add these MediaItems to album at the same time as upload.
+	// Uploaded files are removed. If a file can't be removed, or some items fail to be created,
+	// the MediaItems that were created are returned with the error.
 	Upload(client *http.Client, filePaths []string) ([]MediaItem, error)
 
 	// UploadWithAlbum is a method to upload MediaItems to GooglePhotos with it added to the Album.
 	UploadWithAlbum(client *http.Client, filePaths []string, album Album) ([]MediaItem, error)
 
 	// UploadWithAlbumname is a method to upload MediaItems to GooglePhotos with it added to a specific name Album.
-	// If the Album does not exist in GooglePhotos, it will be created.
+	// If no Album with the name can be added to by the app, it will be created.
+	// Use AlbumResolver to choose other policies.
 	UploadWithAlbumname(client *http.Client, filePaths []string, albumname string) (Album, []MediaItem, error)
+
+	// WaitForVideos is a method that waits until the videos among uploaded MediaItems are processed.
+	// It returns the outcomes of the videos only. See WaitForVideoProcessing.
+	WaitForVideos(client *http.Client, items []MediaItem, options VideoWaitOptions) ([]VideoWaitOutcome, error)
 }
 
 type uploadMethods struct{}
@@ -47,12 +54,7 @@ func (uploader uploadMethods) UploadWithAlbum(client *http.Client, filePaths []s
 		return nil, err
 	}
 
-	items, err := upload(client, req)
-	if err != nil {
-		return nil, err
-	}
-
-	return items, nil
+	return upload(client, req)
 }
 
 func appendMediaItems(req *MediaItemsBatchCreateRequest, client *http.Client, filePaths []string) error {
@@ -83,18 +85,20 @@ func upload(client *http.Client, req MediaItemsBatchCreateRequest) ([]MediaItem,
 		return nil, err
 	}
 
+	// The created MediaItems are returned with the error, so that callers don't upload them again.
 	var items []MediaItem
+	var firstErr error
 	for _, result := range resp.NewMediaItemResults {
 		if reflect.DeepEqual(result.Status.Message, "OK") {
-			if err := os.Remove(result.MediaItem.Description); err != nil {
-				return nil, err
-			}
 			items = append(items, result.MediaItem)
-		} else {
-			return nil, errors.New("NewMediaItemResult.Status.Message is not \"OK\"")
+			if err := os.Remove(result.MediaItem.Description); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		} else if firstErr == nil {
+			firstErr = errors.New("NewMediaItemResult.Status.Message is not \"OK\"")
 		}
 	}
-	return items, nil
+	return items, firstErr
 }
 
 func (uploader uploadMethods) UploadWithAlbumname(client *http.Client, filePaths []string, albumname string) (Album, []MediaItem, error) {
@@ -104,40 +108,16 @@ func (uploader uploadMethods) UploadWithAlbumname(client *http.Client, filePaths
 	}
 
 	items, err := uploader.UploadWithAlbum(client, filePaths, album)
-	if err != nil {
-		return album, nil, err
-	}
-
-	return album, items, nil
+	return album, items, err
 }
 
+// searchAlbum returns an album the app can add media items to, creating it if there is none.
 func searchAlbum(client *http.Client, albumname string) (Album, error) {
-	var nextPageToken string
-	for true {
-		queries := []ListQuery{}
-		queries = append(queries, PageSize(1))
-		if !reflect.DeepEqual(nextPageToken, "") {
-			queries = append(queries, PageToken(nextPageToken))
-		}
-
-		resp, err := Albums.List(client, queries...)
-		if err != nil {
-			return Album{}, err
-		}
-
-		for _, album := range resp.Albums {
-			if reflect.DeepEqual(album.Title, albumname) {
-				return album, nil
-			}
-		}
-
-		nextPageToken = resp.NextPageToken
-		if reflect.DeepEqual(nextPageToken, "") {
-			break
-		}
-	}
-
-	return createAlbum(client, albumname)
+	resolver := NewAlbumResolver(client, AlbumResolverOptions{
+		Missing:   MissingAlbumCreate,
+		Duplicate: DuplicateAlbumWritable,
+	})
+	return resolver.Resolve(albumname)
 }
 
 func createAlbum(client *http.Client, albumname string) (Album, error) {
@@ -154,3 +134,16 @@ func createAlbum(client *http.Client, albumname string) (Album, error) {
 
 	return Album(resp), nil
 }
+
+func (uploader uploadMethods) WaitForVideos(client *http.Client, items []MediaItem, options VideoWaitOptions) ([]VideoWaitOutcome, error) {
+	var ids []string
+	for _, item := range items {
+		if strings.HasPrefix(item.MimeType, "video/") {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return WaitForVideoProcessing(client, ids, options)
+}
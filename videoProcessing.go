@@ -0,0 +1,135 @@
+package gphotos
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VideoWaitOptions is options of WaitForVideoProcessing function.
+type VideoWaitOptions struct {
+	// Timeout is the time after which waiting stops. The default is 10 minutes.
+	Timeout time.Duration
+
+	// Interval is the first interval between polls. It doubles after each poll up to MaxInterval.
+	// The default is 2 seconds.
+	Interval time.Duration
+
+	// MaxInterval is the longest interval between polls. The default is 1 minute.
+	MaxInterval time.Duration
+}
+
+// VideoWaitOutcome is the outcome of waiting for a media item.
+type VideoWaitOutcome struct {
+	MediaItemID string
+	MediaItem   MediaItem
+
+	// Status is Ready or Failed unless TimedOut or Error is set. It is VideoProcessingStatusUnspecified for photos.
+	Status VideoProcessingStatus
+
+	// TimedOut is set when the video was still processing at the deadline.
+	TimedOut bool
+
+	// Error is the status message of MediaItems.BatchGet when the media item couldn't be retrieved.
+	Error string
+}
+
+// Done reports whether the media item is ready to be used.
+func (outcome VideoWaitOutcome) Done() bool {
+	return !outcome.TimedOut && outcome.Error == "" && outcome.Status != Failed
+}
+
+// WaitForVideoProcessing polls MediaItems.BatchGet with backoff until each video reaches Ready or Failed,
+// or until the timeout passes. Outcomes are returned in the order of mediaItemIDs.
+// Photos are complete as soon as they are retrieved. The returned error is only set when a request fails.
+func WaitForVideoProcessing(client *http.Client, mediaItemIDs []string, options VideoWaitOptions) ([]VideoWaitOutcome, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Minute
+	}
+	if options.Interval <= 0 {
+		options.Interval = 2 * time.Second
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = time.Minute
+	}
+	deadline := time.Now().Add(options.Timeout)
+
+	outcomes := make([]VideoWaitOutcome, len(mediaItemIDs))
+	pending := map[string][]int{}
+	for i, id := range mediaItemIDs {
+		outcomes[i].MediaItemID = id
+		pending[id] = append(pending[id], i)
+	}
+
+	interval := options.Interval
+	for {
+		ids := make([]string, 0, len(pending))
+		seen := map[string]bool{}
+		for _, id := range mediaItemIDs {
+			if _, ok := pending[id]; ok && !seen[id] {
+				ids = append(ids, id)
+				seen[id] = true
+			}
+		}
+		for len(ids) > 0 {
+			n := len(ids)
+			if n > maxBatchSize {
+				n = maxBatchSize
+			}
+			queries := make([]MediaItemsBatchGetQuery, 0, n)
+			for _, id := range ids[:n] {
+				queries = append(queries, MediaItemIDs(id))
+			}
+			resp, err := MediaItems.BatchGet(client, queries...)
+			if err != nil {
+				return outcomes, err
+			}
+			for j, result := range resp.MediaItemResults {
+				if j >= n {
+					break
+				}
+				// Results are returned in the order of the requested IDs.
+				id := ids[j]
+				done := true
+				for _, i := range pending[id] {
+					outcome := &outcomes[i]
+					if result.Status.Message != "" && result.Status.Message != "OK" {
+						outcome.Error = result.Status.Message
+						continue
+					}
+					outcome.MediaItem = result.MediaItem
+					outcome.Status = result.MediaItem.MediaMetadata.Video.Status
+					if strings.HasPrefix(result.MediaItem.MimeType, "video/") && outcome.Status != Ready && outcome.Status != Failed {
+						done = false
+					}
+				}
+				if done {
+					delete(pending, id)
+				}
+			}
+			ids = ids[n:]
+		}
+
+		if len(pending) == 0 {
+			return outcomes, nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			for _, indexes := range pending {
+				for _, i := range indexes {
+					outcomes[i].TimedOut = true
+				}
+			}
+			return outcomes, nil
+		}
+		if interval < remaining {
+			time.Sleep(interval)
+		} else {
+			time.Sleep(remaining)
+		}
+		interval *= 2
+		if interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+}
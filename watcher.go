@@ -0,0 +1,225 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Watcher is the only instance of WatchMethods(https://godoc.org/github.com/Q-Brains/gphotos#WatchMethods).
+var Watcher WatchMethods = watchMethods{}
+
+// WatchMethods is a collection of methods that upload files dropped into a directory.
+// The only instance of WatchMethods is Watcher(https://godoc.org/github.com/Q-Brains/gphotos#Watcher).
+type WatchMethods interface {
+	// Watch is a method that polls a directory and uploads new files with Uploader until stop is closed.
+	// A file is uploaded once its size and modification time have not changed for WatchConfig.StableFor.
+	// Uploaded files are removed by Uploader and recorded in WatchConfig.StateFile, so they are not uploaded twice after a restart.
+	Watch(client *http.Client, config WatchConfig, stop <-chan struct{}) error
+}
+
+// WatchConfig is a configuration of Watcher.Watch method.
+type WatchConfig struct {
+	// Dir is the directory to be watched. Subdirectories are watched too.
+	Dir string
+
+	// Interval is the polling interval. The default is 10 seconds.
+	Interval time.Duration
+
+	// StableFor is how long the size and modification time of a file must stay unchanged before it is uploaded.
+	// The default is 30 seconds.
+	StableFor time.Duration
+
+	// StateFile is the file recording uploaded files. The default is ".gphotos-watch.json" in Dir.
+	StateFile string
+
+	// Rules routes files to albums. The first matching rule is used.
+	// Files matching no rule are uploaded without album.
+	Rules []AlbumRule
+
+	// OnUpload is called after a file has been uploaded. It may be nil.
+	OnUpload func(filePath string, album Album, items []MediaItem)
+
+	// OnError is called when a file fails to be read or uploaded. It may be nil.
+	// The file is retried on the next poll, unless it has been uploaded but couldn't be removed.
+	OnError func(filePath string, err error)
+}
+
+// AlbumRule routes files whose path relative to WatchConfig.Dir matches Pattern to the album titled Albumname.
+// Pattern uses the syntax of filepath.Match. A pattern without separator is matched against the base name.
+type AlbumRule struct {
+	Pattern   string `json:"pattern,omitempty"`
+	Albumname string `json:"albumname,omitempty"`
+}
+
+func (rule AlbumRule) match(relPath string) bool {
+	name := relPath
+	if filepath.Base(rule.Pattern) == rule.Pattern {
+		name = filepath.Base(relPath)
+	}
+	matched, err := filepath.Match(rule.Pattern, name)
+	return err == nil && matched
+}
+
+type watchedFile struct {
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	Uploaded bool      `json:"uploaded,omitempty"`
+	seenAt   time.Time
+}
+
+type watchState struct {
+	Files map[string]*watchedFile `json:"files"`
+}
+
+type watchMethods struct{}
+
+func (watcher watchMethods) Watch(client *http.Client, config WatchConfig, stop <-chan struct{}) error {
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Second
+	}
+	if config.StableFor <= 0 {
+		config.StableFor = 30 * time.Second
+	}
+	if config.StateFile == "" {
+		config.StateFile = filepath.Join(config.Dir, ".gphotos-watch.json")
+	}
+
+	state, err := loadWatchState(config.StateFile)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := watcher.poll(client, config, state); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (watcher watchMethods) poll(client *http.Client, config WatchConfig, state *watchState) error {
+	now := time.Now()
+	stateFile, _ := filepath.Abs(config.StateFile)
+
+	present := map[string]bool{}
+	var ready []string
+	err := filepath.Walk(config.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil && path == config.Dir {
+			return err
+		}
+		if err != nil {
+			// A file renamed or removed during the walk is picked up on the next poll.
+			if !os.IsNotExist(err) && config.OnError != nil {
+				config.OnError(path, err)
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); abs == stateFile || abs == stateFile+".tmp" {
+			return nil
+		}
+		present[path] = true
+
+		file, ok := state.Files[path]
+		if !ok || file.Size != info.Size() || !file.ModTime.Equal(info.ModTime()) {
+			state.Files[path] = &watchedFile{Size: info.Size(), ModTime: info.ModTime(), seenAt: now}
+			return nil
+		}
+		if file.Uploaded {
+			return nil
+		}
+		if file.seenAt.IsZero() {
+			file.seenAt = now
+		}
+		if now.Sub(file.seenAt) >= config.StableFor {
+			ready = append(ready, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Files that disappeared were removed by Uploader or by someone else.
+	for path := range state.Files {
+		if !present[path] {
+			delete(state.Files, path)
+		}
+	}
+
+	sort.Strings(ready)
+	for _, path := range ready {
+		album, items, err := watcher.upload(client, config, path)
+		if len(items) > 0 {
+			// Uploader removes the file, but keep the record in case the removal failed.
+			state.Files[path].Uploaded = true
+			if err := saveWatchState(config.StateFile, state); err != nil {
+				return err
+			}
+			if config.OnUpload != nil {
+				config.OnUpload(path, album, items)
+			}
+		}
+		if err != nil && config.OnError != nil {
+			config.OnError(path, err)
+		}
+	}
+
+	return saveWatchState(config.StateFile, state)
+}
+
+func (watcher watchMethods) upload(client *http.Client, config WatchConfig, path string) (Album, []MediaItem, error) {
+	relPath, err := filepath.Rel(config.Dir, path)
+	if err != nil {
+		return Album{}, nil, err
+	}
+	for _, rule := range config.Rules {
+		if rule.match(relPath) {
+			return Uploader.UploadWithAlbumname(client, []string{path}, rule.Albumname)
+		}
+	}
+	items, err := Uploader.Upload(client, []string{path})
+	return Album{}, items, err
+}
+
+func loadWatchState(stateFile string) (*watchState, error) {
+	state := &watchState{Files: map[string]*watchedFile{}}
+	b, err := ioutil.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+	if state.Files == nil {
+		state.Files = map[string]*watchedFile{}
+	}
+	return state, nil
+}
+
+func saveWatchState(stateFile string, state *watchState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(stateFile+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(stateFile+".tmp", stateFile)
+}
@@ -0,0 +1,448 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// yamlUnmarshal decodes the YAML document b into v like json.Unmarshal.
+// YAML has no schema, so plain scalars read as numbers or booleans are converted back to strings where v expects a string.
+func yamlUnmarshal(b []byte, v interface{}) error {
+	value, err := parseYAML(b)
+	if err != nil {
+		return err
+	}
+	b, err = json.Marshal(yamlCoerce(value, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// parseYAML parses the block style subset of YAML used by manifests into the values json.Unmarshal returns for interface{},
+// with json.Number for numbers: mappings, sequences, plain and quoted scalars, single-line flow collections, and comments.
+// Anchors, tags, multi-line scalars and multiple documents are not supported.
+func parseYAML(b []byte) (interface{}, error) {
+	p := &yamlParser{}
+	for i, text := range strings.Split(string(b), "\n") {
+		text = strings.TrimRight(stripYAMLComment(text), " \t\r")
+		content := strings.TrimLeft(text, " ")
+		if content == "" || (len(p.lines) == 0 && content == "---") {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, yamlError(i+1, "tabs can't be used for indentation")
+		}
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(text) - len(content), content: content})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	value, err := p.node(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, yamlError(p.lines[p.pos].number, "unexpected indentation")
+	}
+	return value, nil
+}
+
+type yamlLine struct {
+	number  int
+	indent  int
+	content string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func yamlError(line int, message string) error {
+	return errors.New("yaml: line " + strconv.Itoa(line) + ": " + message)
+}
+
+// node parses the sequence, mapping or scalar starting at the current line, whose indentation is indent.
+func (p *yamlParser) node(indent int) (interface{}, error) {
+	line := p.lines[p.pos]
+	switch {
+	case isYAMLSequenceItem(line.content):
+		return p.sequence(indent)
+	case yamlKeyEnd(line.content) >= 0:
+		return p.mapping(indent)
+	}
+	p.pos++
+	return yamlScalar(line.number, line.content)
+}
+
+func (p *yamlParser) sequence(indent int) (interface{}, error) {
+	items := []interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSequenceItem(p.lines[p.pos].content) {
+		line := p.lines[p.pos]
+		rest := strings.TrimLeft(line.content[1:], " ")
+		if rest == "" {
+			p.pos++
+			item, err := p.child(indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+		// The content after the dash is parsed as a node indented where it starts, so that following lines can continue it.
+		p.lines[p.pos] = yamlLine{number: line.number, indent: indent + len(line.content) - len(rest), content: rest}
+		item, err := p.node(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	values := map[string]interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		end := yamlKeyEnd(line.content)
+		if end < 0 || isYAMLSequenceItem(line.content) {
+			return nil, yamlError(line.number, "expected a mapping key")
+		}
+		key, err := yamlScalar(line.number, line.content[:end])
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			name = strings.TrimSpace(line.content[:end])
+		}
+		if _, ok := values[name]; ok {
+			return nil, yamlError(line.number, "duplicate key "+strconv.Quote(name))
+		}
+		p.pos++
+
+		rest := strings.TrimSpace(line.content[end+1:])
+		if rest != "" {
+			value, err := yamlScalar(line.number, rest)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+			continue
+		}
+		// A sequence may be indented at the same level as its key.
+		if p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSequenceItem(p.lines[p.pos].content) {
+			value, err := p.sequence(indent)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+			continue
+		}
+		value, err := p.child(indent)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// child parses the node indented more than indent after a key or dash without value, or returns nil if there is none.
+func (p *yamlParser) child(indent int) (interface{}, error) {
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+		return nil, nil
+	}
+	return p.node(p.lines[p.pos].indent)
+}
+
+func isYAMLSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// yamlKeyEnd returns the index of the colon ending the mapping key of content, or -1 if content is not a key.
+func yamlKeyEnd(content string) int {
+	if strings.HasPrefix(content, "[") || strings.HasPrefix(content, "{") {
+		return -1
+	}
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(content) || content[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// stripYAMLComment removes a comment starting with # at the beginning or after a space, outside quotes.
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" :-[{,", rune(text[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// yamlScalar returns the value of a scalar or a flow collection.
+func yamlScalar(number int, text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "" || text == "~" || text == "null" || text == "Null" || text == "NULL":
+		return nil, nil
+	case text == "true" || text == "True" || text == "TRUE":
+		return true, nil
+	case text == "false" || text == "False" || text == "FALSE":
+		return false, nil
+	case text == "|" || text == ">" || strings.HasPrefix(text, "|-") || strings.HasPrefix(text, ">-") || strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "!"):
+		return nil, yamlError(number, "unsupported YAML syntax "+strconv.Quote(text))
+	case strings.HasPrefix(text, "\""):
+		var s string
+		if !strings.HasSuffix(text, "\"") || len(text) < 2 || json.Unmarshal([]byte(text), &s) != nil {
+			return nil, yamlError(number, "invalid double-quoted string")
+		}
+		return s, nil
+	case strings.HasPrefix(text, "'"):
+		if !strings.HasSuffix(text, "'") || len(text) < 2 {
+			return nil, yamlError(number, "invalid single-quoted string")
+		}
+		return strings.Replace(text[1:len(text)-1], "''", "'", -1), nil
+	case strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{"):
+		f := &yamlFlow{number: number, text: text}
+		value, err := f.value(false)
+		if err != nil {
+			return nil, err
+		}
+		if f.skipSpaces(); f.pos < len(f.text) {
+			return nil, yamlError(number, "unexpected "+strconv.Quote(f.text[f.pos:])+" after flow collection")
+		}
+		return value, nil
+	}
+	if (text[0] == '-' || (text[0] >= '0' && text[0] <= '9')) && json.Valid([]byte(text)) {
+		return json.Number(text), nil
+	}
+	return text, nil
+}
+
+// yamlFlow parses a flow collection on one line. Flow collections can be nested.
+type yamlFlow struct {
+	number int
+	text   string
+	pos    int
+}
+
+func (f *yamlFlow) skipSpaces() {
+	for f.pos < len(f.text) && (f.text[f.pos] == ' ' || f.text[f.pos] == '\t') {
+		f.pos++
+	}
+}
+
+// value parses the flow collection or scalar at the current position.
+// A plain scalar ends at a comma or a closing bracket, and a mapping key also at a colon followed by a space.
+func (f *yamlFlow) value(key bool) (interface{}, error) {
+	f.skipSpaces()
+	if f.pos >= len(f.text) {
+		return nil, yamlError(f.number, "unterminated flow collection")
+	}
+	start := f.pos
+	switch c := f.text[f.pos]; c {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		for f.pos++; f.pos < len(f.text); f.pos++ {
+			switch {
+			case c == '"' && f.text[f.pos] == '\\':
+				f.pos++
+			case c == '\'' && strings.HasPrefix(f.text[f.pos:], "''"):
+				f.pos++
+			case f.text[f.pos] == c:
+				f.pos++
+				return yamlScalar(f.number, f.text[start:f.pos])
+			}
+		}
+		return nil, yamlError(f.number, "unterminated quoted string")
+	}
+	for f.pos < len(f.text) && !strings.ContainsRune(",]}", rune(f.text[f.pos])) {
+		if key && f.text[f.pos] == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ') {
+			break
+		}
+		f.pos++
+	}
+	return yamlScalar(f.number, f.text[start:f.pos])
+}
+
+func (f *yamlFlow) sequence() (interface{}, error) {
+	items := []interface{}{}
+	f.pos++
+	for {
+		if f.skipSpaces(); f.pos < len(f.text) && f.text[f.pos] == ']' {
+			f.pos++
+			return items, nil
+		}
+		item, err := f.value(false)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if done, err := f.next(']'); err != nil || done {
+			return items, err
+		}
+	}
+}
+
+func (f *yamlFlow) mapping() (interface{}, error) {
+	values := map[string]interface{}{}
+	f.pos++
+	for {
+		if f.skipSpaces(); f.pos < len(f.text) && f.text[f.pos] == '}' {
+			f.pos++
+			return values, nil
+		}
+		start := f.pos
+		key, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			name = strings.TrimSpace(f.text[start:f.pos])
+		}
+		if _, ok := values[name]; ok {
+			return nil, yamlError(f.number, "duplicate key "+strconv.Quote(name))
+		}
+		var value interface{}
+		if f.skipSpaces(); f.pos < len(f.text) && f.text[f.pos] == ':' {
+			f.pos++
+			if value, err = f.value(false); err != nil {
+				return nil, err
+			}
+		}
+		values[name] = value
+		if done, err := f.next('}'); err != nil || done {
+			return values, err
+		}
+	}
+}
+
+// next consumes the comma after an item, or the closing bracket, which returns true.
+func (f *yamlFlow) next(closing byte) (bool, error) {
+	f.skipSpaces()
+	switch {
+	case f.pos >= len(f.text):
+		return false, yamlError(f.number, "unterminated flow collection")
+	case f.text[f.pos] == ',':
+		f.pos++
+		return false, nil
+	case f.text[f.pos] == closing:
+		f.pos++
+		return true, nil
+	}
+	return false, yamlError(f.number, "expected , or "+string(closing)+" in flow collection")
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// yamlCoerce converts the numbers and booleans of value to strings where t expects a string.
+// Types that implement json.Unmarshaler decode their values themselves, so they are left as is.
+func yamlCoerce(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return value
+	}
+	switch v := value.(type) {
+	case json.Number:
+		if t.Kind() == reflect.String {
+			return string(v)
+		}
+	case bool:
+		if t.Kind() == reflect.String {
+			return strconv.FormatBool(v)
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = yamlCoerce(item, t.Elem())
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			switch t.Kind() {
+			case reflect.Map:
+				v[key] = yamlCoerce(item, t.Elem())
+			case reflect.Struct:
+				if field, ok := yamlField(t, key); ok {
+					v[key] = yamlCoerce(item, field.Type)
+				}
+			}
+		}
+	}
+	return value
+}
+
+// yamlField returns the field of the struct type t that json.Unmarshal decodes the key into.
+func yamlField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var folded reflect.StructField
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if field, ok := yamlField(embedded, key); ok {
+					return field, true
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return field, true
+		}
+		if !found && strings.EqualFold(name, key) {
+			folded, found = field, true
+		}
+	}
+	return folded, found
+}
@@ -0,0 +1,90 @@
+package gphotos
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	for _, c := range []struct {
+		yaml string
+		json string
+	}{
+		{"", `null`},
+		{"a: 1\nb: true\nc: ~\nd: text # comment", `{"a":1,"b":true,"c":null,"d":"text"}`},
+		{"a: \"x # y\"\nb: 'it''s'", `{"a":"x # y","b":"it's"}`},
+		{"- a\n- b: 1\n  c: 2\n-\n  - 3", `["a",{"b":1,"c":2},[3]]`},
+		{"a:\n- 1\n- 2\nb: []\nc: {}", `{"a":[1,2],"b":[],"c":{}}`},
+		{"a: [1, 'x, y', \"z\"]", `{"a":[1,"x, y","z"]}`},
+		{"latlng: {latitude: 48.8, longitude: 2.3}", `{"latlng":{"latitude":48.8,"longitude":2.3}}`},
+		{"a: {b: [1, {c: d}], e: {}, 'f: g': http://example.com}", `{"a":{"b":[1,{"c":"d"}],"e":{},"f: g":"http://example.com"}}`},
+		{"- {key: a, title: b}\n- [x, [y, z],]", `[{"key":"a","title":"b"},["x",["y","z"]]]`},
+	} {
+		value, err := parseYAML([]byte(c.yaml))
+		if err != nil {
+			t.Errorf("parseYAML(%q) returned error: %v", c.yaml, err)
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, want interface{}
+		json.Unmarshal(b, &got)
+		json.Unmarshal([]byte(c.json), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseYAML(%q) = %s, want %s", c.yaml, b, c.json)
+		}
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	for _, yaml := range []string{
+		"a: 1\na: 2",
+		"a: {b: 1, b: 2}",
+		"a: [1, 2",
+		"a: {b: 1",
+		"a: [1] x",
+		"a: [1 2] ]",
+		"a: {b: 'c}",
+		"a: &anchor 1",
+		"a: |",
+		"a:\n\t- 1",
+	} {
+		if _, err := parseYAML([]byte(yaml)); err == nil {
+			t.Errorf("parseYAML(%q) returned no error", yaml)
+		}
+	}
+}
+
+func TestYAMLUnmarshalAlbumManifest(t *testing.T) {
+	for _, c := range []struct {
+		yaml string
+		want AlbumManifest
+	}{
+		{
+			"albums:\n- key: 2024\n  title: 2024\n  mediaItemIds: [123, true]\n  cover: 1.50",
+			AlbumManifest{Albums: []AlbumSpec{{Key: "2024", Title: "2024", MediaItemIDs: []string{"123", "true"}, Cover: "1.50"}}},
+		},
+		{
+			"albums:\n- key: paris\n  title: Paris\n  enrichments:\n  - locationEnrichment: {location: {locationName: 75, latlng: {latitude: 48.8, longitude: 2.3}}}",
+			AlbumManifest{Albums: []AlbumSpec{{Key: "paris", Title: "Paris", Enrichments: []NewEnrichmentItem{
+				{LocationEnrichment: LocationEnrichment{Location: Location{LocationName: "75", Latlng: LatLng{Latitude: 48.8, Longitude: 2.3}}}},
+			}}}},
+		},
+		{
+			"albums:\n- {key: shared, title: Shared, share: {isCollaborative: true}}",
+			AlbumManifest{Albums: []AlbumSpec{{Key: "shared", Title: "Shared", Share: &SharedAlbumOptions{IsCollaborative: true}}}},
+		},
+	} {
+		var got AlbumManifest
+		if err := yamlUnmarshal([]byte(c.yaml), &got); err != nil {
+			t.Errorf("yamlUnmarshal(%q) returned error: %v", c.yaml, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("yamlUnmarshal(%q) = %+v, want %+v", c.yaml, got, c.want)
+		}
+	}
+}